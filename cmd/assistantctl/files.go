@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yonisaka/assistant/internal/di"
+	"github.com/yonisaka/assistant/internal/entities/request"
+)
+
+const filesUsage = `Usage: assistantctl files -dir <folder> [flags]
+
+Sync the files of the folder into files of an assistant and print the report as JSON.
+New and changed files are uploaded, removed files are deleted, then file_ids of the assistant are updated.
+
+Flags:
+`
+
+// runFiles is a function to parse flags of files command and sync the folder
+func runFiles(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("files", flag.ExitOnError)
+	dir := flags.String("dir", "", "folder to sync")
+	assistantID := flags.String("assistant", os.Getenv("OPENAI_ASSISTANT_ID"), "assistant to attach the files to")
+	extensions := flags.String("ext", "", "comma separated extensions to sync, e.g. .md,.pdf, empty syncs every file")
+	dryRun := flags.Bool("dry-run", false, "print the changes without applying them")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, filesUsage)
+		flags.PrintDefaults()
+	}
+
+	_ = flags.Parse(args)
+
+	if *dir == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	report, err := di.GetFileUsecase().SyncFiles(ctx, &request.FileSync{
+		AssistantID: *assistantID,
+		DryRun:      *dryRun,
+		Dir:         *dir,
+		Extensions:  parseExtensions(*extensions),
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// parseExtensions is a function to normalize comma separated extensions into lowercase with leading dot
+func parseExtensions(value string) []string {
+	var extensions []string
+
+	for _, extension := range strings.Split(value, ",") {
+		if extension = strings.TrimSpace(extension); extension != "" {
+			extensions = append(extensions, "."+strings.TrimPrefix(strings.ToLower(extension), "."))
+		}
+	}
+
+	return extensions
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/yonisaka/assistant/internal/di"
+	"github.com/yonisaka/assistant/internal/entities/response"
+)
+
+const usage = `Usage: assistantctl <command> -f <manifest> [flags]
+
+Commands:
+  plan    Print changes to sync the manifest with OpenAI
+  sync    Print changes and apply them after confirmation
+  files   Sync a folder into files of an assistant, see assistantctl files -h
+
+Flags:
+`
+
+const maxDiffValueLength = 80
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	command := os.Args[1]
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if command == "files" {
+		if err := runFiles(ctx, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	file := flags.String("f", "assistants.yaml", "manifest of desired assistants, YAML or JSON")
+	prune := flags.Bool("prune", false, "delete managed assistants that are not in the manifest")
+	autoApprove := flags.Bool("auto-approve", false, "apply changes without confirmation")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flags.PrintDefaults()
+	}
+
+	if command != "plan" && command != "sync" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	_ = flags.Parse(os.Args[2:])
+
+	if err := run(ctx, command, *file, *prune, *autoApprove); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run is a function to plan the manifest and apply it when the command is sync
+func run(ctx context.Context, command, file string, prune, autoApprove bool) error {
+	manifest, err := loadManifest(file)
+	if err != nil {
+		return err
+	}
+
+	assistantUsecase := di.GetAssistantUsecase()
+
+	plan, err := assistantUsecase.PlanSync(ctx, manifest, prune)
+	if err != nil {
+		return err
+	}
+
+	printPlan(os.Stdout, plan)
+
+	if command == "plan" || !plan.HasChanges() {
+		return nil
+	}
+
+	if !autoApprove && !confirm(os.Stdin, os.Stdout) {
+		fmt.Println("Apply cancelled.")
+		return nil
+	}
+
+	if err := assistantUsecase.ApplySync(ctx, plan); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nApply complete! Resources: %d created, %d updated, %d deleted.\n",
+		plan.Count(response.AssistantChangeCreate),
+		plan.Count(response.AssistantChangeUpdate),
+		plan.Count(response.AssistantChangeDelete),
+	)
+
+	for _, change := range plan.Changes {
+		if change.Action == response.AssistantChangeCreate {
+			fmt.Printf("  %s = %s\n", change.Key, change.AssistantID)
+		}
+	}
+
+	return nil
+}
+
+// printPlan is a function to print the plan like terraform plan
+func printPlan(w io.Writer, plan *response.AssistantSyncPlan) {
+	if !plan.HasChanges() {
+		fmt.Fprintln(w, "No changes. Assistants are up-to-date.")
+		return
+	}
+
+	fmt.Fprintln(w, "Assistant sync plan:")
+	fmt.Fprintln(w)
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case response.AssistantChangeCreate:
+			fmt.Fprintf(w, "  + %s (%s) will be created\n", change.Key, change.Name)
+		case response.AssistantChangeUpdate:
+			fmt.Fprintf(w, "  ~ %s (%s) will be updated\n", change.Key, change.AssistantID)
+		case response.AssistantChangeDelete:
+			fmt.Fprintf(w, "  - %s (%s) will be deleted\n", change.Key, change.AssistantID)
+		default:
+			continue
+		}
+
+		for _, diff := range change.Diffs {
+			fmt.Fprintf(w, "      %s: %s -> %s\n", diff.Field, diffValue(diff.Before), diffValue(diff.After))
+		}
+	}
+
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		plan.Count(response.AssistantChangeCreate),
+		plan.Count(response.AssistantChangeUpdate),
+		plan.Count(response.AssistantChangeDelete),
+	)
+}
+
+// diffValue is a function to quote the value and shorten the long one
+func diffValue(value string) string {
+	runes := []rune(value)
+	if len(runes) > maxDiffValueLength {
+		value = string(runes[:maxDiffValueLength]) + "..."
+	}
+
+	return fmt.Sprintf("%q", value)
+}
+
+// confirm is a function to ask the user to apply the plan, only yes is accepted
+func confirm(r io.Reader, w io.Writer) bool {
+	fmt.Fprint(w, "\nDo you want to apply these changes? Only 'yes' will be accepted: ")
+
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	return strings.TrimSpace(answer) == "yes"
+}
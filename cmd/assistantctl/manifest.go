@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"gopkg.in/yaml.v3"
+)
+
+// loadManifest is a function to read assistant manifest from YAML or JSON file, unknown field is rejected
+// YAML is converted into JSON first, so tool parameters are kept as JSON schema
+func loadManifest(path string) (*request.AssistantManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.DisallowUnknownFields()
+
+	var manifest request.AssistantManifest
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
@@ -10,7 +10,13 @@ import (
 
 func main() {
 	// Create new Fiber instance
-	app := fiber.New()
+	// Request body is streamed so uploaded file is not read into memory,
+	// multipart form is not pre-parsed for the same reason
+	// Body of other endpoints is limited by body limit middleware of the router
+	app := fiber.New(fiber.Config{
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
+	})
 
 	// Logging Request ID
 	app.Use(requestid.New())
@@ -0,0 +1,28 @@
+package httphandler
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminConfig is a struct of admin credential, admin endpoints are disabled when Key is empty
+type AdminConfig struct {
+	Key string
+}
+
+// NewAdminGuard is a function to get middleware that only lets admin through, the key is sent in HeaderAdminKey
+// Admin endpoints manage what every owner shares, e.g. files of the OpenAI account and assistants
+func NewAdminGuard(config AdminConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if config.Key == "" {
+			return fiber.NewError(fiber.StatusForbidden, "admin endpoint is disabled")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.Get(HeaderAdminKey)), []byte(config.Key)) != 1 {
+			return fiber.NewError(fiber.StatusUnauthorized, "admin key is invalid")
+		}
+
+		return c.Next()
+	}
+}
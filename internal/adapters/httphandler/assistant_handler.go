@@ -0,0 +1,89 @@
+package httphandler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+type assistantHandler struct {
+	assistantUsecase usecases.AssistantUsecase
+}
+
+func NewAssistantHandler(assistantUsecase usecases.AssistantUsecase) AssistantHandler {
+	return &assistantHandler{
+		assistantUsecase: assistantUsecase,
+	}
+}
+
+type AssistantHandler interface {
+	CreateAssistant(c *fiber.Ctx) error
+	GetListAssistant(c *fiber.Ctx) error
+	GetAssistant(c *fiber.Ctx) error
+	UpdateAssistant(c *fiber.Ctx) error
+	DeleteAssistant(c *fiber.Ctx) error
+}
+
+func (h *assistantHandler) CreateAssistant(c *fiber.Ctx) error {
+	assistant := new(request.Assistant)
+
+	if err := c.BodyParser(assistant); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	result, err := h.assistantUsecase.CreateAssistant(c.Context(), assistant)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *assistantHandler) GetListAssistant(c *fiber.Ctx) error {
+	result, err := h.assistantUsecase.GetListAssistant(c.Context())
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *assistantHandler) GetAssistant(c *fiber.Ctx) error {
+	result, err := h.assistantUsecase.GetAssistant(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *assistantHandler) UpdateAssistant(c *fiber.Ctx) error {
+	assistant := new(request.Assistant)
+
+	if err := c.BodyParser(assistant); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	result, err := h.assistantUsecase.UpdateAssistant(c.Context(), c.Params("id"), assistant)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *assistantHandler) DeleteAssistant(c *fiber.Ctx) error {
+	if err := h.assistantUsecase.DeleteAssistant(c.Context(), c.Params("id")); err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
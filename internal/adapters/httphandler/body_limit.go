@@ -0,0 +1,57 @@
+package httphandler
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// BodyLimitConfig is a struct of request body limit, Limit is in bytes
+// Next skips the limit for the request, e.g. file upload that streams its own body with its own limit
+type BodyLimitConfig struct {
+	Limit int
+	Next  func(c *fiber.Ctx) bool
+}
+
+// NewBodyLimit is a function to get middleware that reads request body up to the limit
+// Request body is streamed for file upload, so fasthttp no longer rejects large body by itself
+// and reading body of other endpoints, e.g. by BodyParser, would read the whole stream into memory
+func NewBodyLimit(config BodyLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if config.Next != nil && config.Next(c) {
+			return c.Next()
+		}
+
+		if c.Request().Header.ContentLength() > config.Limit {
+			return bodyTooLarge(c)
+		}
+
+		stream := c.Context().RequestBodyStream()
+		if stream == nil {
+			return c.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(stream, int64(config.Limit)+1))
+		if err != nil {
+			log.Warn(err)
+			return fiber.ErrBadRequest
+		}
+
+		if len(body) > config.Limit {
+			return bodyTooLarge(c)
+		}
+
+		c.Request().SetBody(body)
+
+		return c.Next()
+	}
+}
+
+// bodyTooLarge is a function to reject request body over the limit
+// The rest of the body is not read, so the connection cannot serve the next request
+func bodyTooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+
+	return fiber.ErrRequestEntityTooLarge
+}
@@ -0,0 +1,103 @@
+package httphandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+const (
+	runErrorCodeRateLimitExceeded = "rate_limit_exceeded"
+	runErrorCodeInvalidPrompt     = "invalid_prompt"
+)
+
+// toFiberError is a function to map usecase error into HTTP error
+// OpenAI API errors are mapped by their status, run errors by their terminal status,
+// the rest will be internal server error
+func toFiberError(err error) error {
+	if runErr, ok := connector.AsRunError(err); ok {
+		return runFiberError(runErr)
+	}
+
+	switch {
+	case errors.Is(err, repository.ErrConversationNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, usecases.ErrRunNotFound), errors.Is(err, usecases.ErrThreadFileNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, usecases.ErrRunOptionNotAllowed), errors.Is(err, usecases.ErrRunOptionInvalid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrAssistantInvalid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrFileInvalid), errors.Is(err, usecases.ErrListOptionInvalid),
+		errors.Is(err, usecases.ErrFileSyncInvalid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrThreadInvalid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrAttachmentInvalid):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrAttachmentNotAllowed):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, usecases.ErrProfileNotFound):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, usecases.ErrProfileRateLimited), errors.Is(err, usecases.ErrTooManyRuns):
+		return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+	case errors.Is(err, usecases.ErrRunNotCancellable):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, usecases.ErrRunPollTimeout), errors.Is(err, context.DeadlineExceeded):
+		return fiber.NewError(fiber.StatusGatewayTimeout, err.Error())
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return fiber.NewError(fiber.StatusBadGateway, err.Error())
+	case connector.IsRateLimited(err):
+		return fiber.ErrTooManyRequests
+	case connector.IsNotFound(err):
+		return fiber.ErrNotFound
+	case connector.IsBadRequest(err):
+		return badRequestFiberError(err)
+	case connector.IsAuth(err), connector.IsServerError(err):
+		return fiber.ErrBadGateway
+	default:
+		return fiber.ErrInternalServerError
+	}
+}
+
+// badRequestFiberError is a function to map request rejected by OpenAI into HTTP error
+// Only the message is returned to the client, the type, code and request id of OpenAI are logged instead
+func badRequestFiberError(err error) error {
+	apiErr, ok := connector.AsAPIError(err)
+	if !ok {
+		return fiber.ErrBadRequest
+	}
+
+	log.Warnw("OpenAI Request Rejected:", "request_id", apiErr.RequestID, "error", err)
+
+	if apiErr.Message == "" {
+		return fiber.ErrBadRequest
+	}
+
+	return fiber.NewError(fiber.StatusBadRequest, apiErr.Message)
+}
+
+// runFiberError is a function to map run that ends without completing into HTTP error
+func runFiberError(runErr *connector.RunError) error {
+	switch {
+	case errors.Is(runErr, connector.ErrRunExpired):
+		return fiber.NewError(fiber.StatusGatewayTimeout, "assistant run expired before completing")
+	case errors.Is(runErr, connector.ErrRunCancelled):
+		return fiber.NewError(fiber.StatusConflict, "assistant run was cancelled")
+	case errors.Is(runErr, connector.ErrRunIncomplete):
+		return fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("assistant run incomplete: %s", runErr.Code))
+	case runErr.Code == runErrorCodeRateLimitExceeded:
+		return fiber.NewError(fiber.StatusTooManyRequests, runErr.Message)
+	case runErr.Code == runErrorCodeInvalidPrompt:
+		return fiber.NewError(fiber.StatusUnprocessableEntity, runErr.Message)
+	default:
+		return fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("assistant run failed: %s", runErr.Message))
+	}
+}
@@ -1,31 +1,269 @@
 package httphandler
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"path/filepath"
+	"slices"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/usecases"
 )
 
+const (
+	// uploadFormOverhead is the room for multipart boundaries, headers and purpose field of upload body
+	uploadFormOverhead = 64 * 1024
+	// maxPurposeLength is the maximum length of purpose field that is read from upload body
+	maxPurposeLength = 64
+)
+
+var errFileTooLarge = errors.New("file is too large")
+
+// FileUploadConfig is a struct of limits of file uploaded by client
+// MaxSize is in bytes and zero means no limit,
+// AllowedExtensions are lowercase with leading dot and empty means every extension is allowed
+type FileUploadConfig struct {
+	MaxSize           int64
+	AllowedExtensions []string
+}
+
+// FileSyncConfig is a struct of local folder synced into files of an assistant
+// Sync is disabled when Dir is empty, AssistantID is used when the request does not choose one,
+// the request can only choose AssistantID or one of AssistantIDs
+type FileSyncConfig struct {
+	Dir          string
+	AssistantID  string
+	AssistantIDs []string
+}
+
 type fileHandler struct {
-	fileUsecase usecases.FileUsecase
+	fileUsecase  usecases.FileUsecase
+	uploadConfig FileUploadConfig
+	syncConfig   FileSyncConfig
 }
 
-func NewFileHandler(fileUsecase usecases.FileUsecase) FileHandler {
+func NewFileHandler(fileUsecase usecases.FileUsecase, uploadConfig FileUploadConfig, syncConfig FileSyncConfig) FileHandler {
 	return &fileHandler{
-		fileUsecase: fileUsecase,
+		fileUsecase:  fileUsecase,
+		uploadConfig: uploadConfig,
+		syncConfig:   syncConfig,
 	}
 }
 
 type FileHandler interface {
 	GetListFile(c *fiber.Ctx) error
+	UploadFile(c *fiber.Ctx) error
+	GetFile(c *fiber.Ctx) error
+	DeleteFile(c *fiber.Ctx) error
+	GetFileContent(c *fiber.Ctx) error
+	SyncFiles(c *fiber.Ctx) error
 }
 
 func (h *fileHandler) GetListFile(c *fiber.Ctx) error {
-	result, err := h.fileUsecase.GetListFile(c.Context())
+	option := new(request.ListFile)
+
+	if err := c.QueryParser(option); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	result, err := h.fileUsecase.GetListFile(c.Context(), option)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *fileHandler) GetFile(c *fiber.Ctx) error {
+	result, err := h.fileUsecase.GetFile(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *fileHandler) DeleteFile(c *fiber.Ctx) error {
+	if err := h.fileUsecase.DeleteFile(c.Context(), c.Params("id")); err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetFileContent is a function to stream raw content of file to client as it is read from OpenAI API
+// The body is closed by the server after it is sent
+func (h *fileHandler) GetFileContent(c *fiber.Ctx) error {
+	result, err := h.fileUsecase.GetFileContent(c.Context(), c.Params("id"))
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return sendFileContent(c, result)
+}
+
+// sendFileContent is a function to stream file content from OpenAI API to the client
+func sendFileContent(c *fiber.Ctx, content *response.FileContent) error {
+	c.Set(fiber.HeaderContentType, content.ContentType)
+	if content.ContentDisposition != "" {
+		c.Set(fiber.HeaderContentDisposition, content.ContentDisposition)
+	}
+
+	return c.SendStream(content.Body, int(content.ContentLength))
+}
+
+// SyncFiles is a function to sync the configured folder into files of an assistant
+// The folder and the extensions come from config, the client only chooses the assistant and dry run
+func (h *fileHandler) SyncFiles(c *fiber.Ctx) error {
+	if h.syncConfig.Dir == "" {
+		return fiber.NewError(fiber.StatusNotFound, "file sync is not configured")
+	}
+
+	sync := new(request.FileSync)
+
+	if err := c.BodyParser(sync); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	switch {
+	case sync.AssistantID == "":
+		sync.AssistantID = h.syncConfig.AssistantID
+	case sync.AssistantID != h.syncConfig.AssistantID && !slices.Contains(h.syncConfig.AssistantIDs, sync.AssistantID):
+		return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("assistant_id %s is not allowed to sync", sync.AssistantID))
+	}
+
+	sync.Dir = h.syncConfig.Dir
+	sync.Extensions = h.uploadConfig.AllowedExtensions
+
+	result, err := h.fileUsecase.SyncFiles(c.Context(), sync)
 	if err != nil {
 		log.Warn(err)
-		return fiber.ErrInternalServerError
+		return toFiberError(err)
 	}
 
 	return c.JSON(result)
 }
+
+// UploadFile is a function to stream multipart upload into OpenAI API without reading the whole file into memory
+// The purpose is read from the form field sent before the file or from the purpose query
+func (h *fileHandler) UploadFile(c *fiber.Ctx) error {
+	_, params, err := mime.ParseMediaType(string(c.Request().Header.ContentType()))
+	if err != nil || params["boundary"] == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "multipart/form-data body is required")
+	}
+
+	// Reject early when the declared body is larger than the file can be
+	contentLength := int64(c.Request().Header.ContentLength())
+	if h.uploadConfig.MaxSize > 0 && contentLength > h.uploadConfig.MaxSize+uploadFormOverhead {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, errFileTooLarge.Error())
+	}
+
+	// Body is only streamed when the server is configured with StreamRequestBody
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	purpose := c.Query("purpose")
+	reader := multipart.NewReader(body, params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return fiber.NewError(fiber.StatusBadRequest, "file is required")
+		}
+
+		if err != nil {
+			log.Warn(err)
+			return fiber.ErrBadRequest
+		}
+
+		switch part.FormName() {
+		case "purpose":
+			value, err := io.ReadAll(io.LimitReader(part, maxPurposeLength))
+			if err != nil {
+				log.Warn(err)
+				return fiber.ErrBadRequest
+			}
+
+			purpose = string(value)
+		case "file":
+			return h.uploadFile(c, part, purpose)
+		}
+	}
+}
+
+// uploadFile is a function to validate the file part and upload it
+func (h *fileHandler) uploadFile(c *fiber.Ctx, part *multipart.Part, purpose string) error {
+	filename := filepath.Base(part.FileName())
+	if part.FileName() == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "file name is required")
+	}
+
+	extension := strings.ToLower(filepath.Ext(filename))
+	if len(h.uploadConfig.AllowedExtensions) > 0 && !slices.Contains(h.uploadConfig.AllowedExtensions, extension) {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("file extension %q is not allowed", extension))
+	}
+
+	var file io.Reader = part
+	if h.uploadConfig.MaxSize > 0 {
+		file = &maxSizeReader{reader: part, remaining: h.uploadConfig.MaxSize}
+	}
+
+	result, err := h.fileUsecase.UploadFile(c.Context(), &request.UploadFile{
+		Purpose:     purpose,
+		Filename:    filename,
+		ContentType: part.Header.Get(fiber.HeaderContentType),
+		Reader:      file,
+	})
+	if errors.Is(err, errFileTooLarge) {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, errFileTooLarge.Error())
+	}
+
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// maxSizeReader is a reader that fails with errFileTooLarge when more than remaining bytes are read
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, errFileTooLarge
+	}
+
+	// Read one byte more than remaining to know whether the file exceeds the limit
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	if r.remaining < 0 {
+		return 0, errFileTooLarge
+	}
+
+	return n, err
+}
@@ -0,0 +1,25 @@
+package httphandler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const (
+	// HeaderUserID is the header of the caller identity, it is set by the gateway in front of this service
+	HeaderUserID = "X-User-Id"
+	// HeaderAdminKey is the header of the admin credential, see AdminConfig
+	HeaderAdminKey = "X-Admin-Key"
+)
+
+// ownerID is a function to get the caller identity from header
+// Request without identity is rejected, otherwise every such caller would share the same conversations and runs
+// Header value is only valid within the handler, so it is copied because runs and conversations keep it
+func ownerID(c *fiber.Ctx) (string, error) {
+	owner := c.Get(HeaderUserID)
+	if owner == "" {
+		return "", fiber.NewError(fiber.StatusUnauthorized, HeaderUserID+" header is required")
+	}
+
+	return utils.CopyString(owner), nil
+}
@@ -1,27 +1,144 @@
 package httphandler
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/usecases"
 )
 
 type promptHandler struct {
 	promptUsecase usecases.PromptUsecase
+	config        PromptConfig
+}
+
+// PromptConfig is a struct of prompt handler config
+// Timeout bounds synchronous prompt, it should be below the timeout of the load balancer in front of this service,
+// because fasthttp does not cancel the request context when the client is gone, zero means no timeout
+type PromptConfig struct {
+	Timeout time.Duration
 }
 
-func NewPromptHandler(promptUsecase usecases.PromptUsecase) PromptHandler {
+func NewPromptHandler(promptUsecase usecases.PromptUsecase, config PromptConfig) PromptHandler {
 	return &promptHandler{
 		promptUsecase: promptUsecase,
+		config:        config,
 	}
 }
 
 type PromptHandler interface {
 	SendPrompt(c *fiber.Ctx) error
+	StreamPrompt(c *fiber.Ctx) error
+	SubmitPrompt(c *fiber.Ctx) error
+	GetRun(c *fiber.Ctx) error
+	CancelRun(c *fiber.Ctx) error
+	GetListMessage(c *fiber.Ctx) error
 }
 
 func (h *promptHandler) SendPrompt(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	prompt := new(request.Prompt)
+
+	if err := c.BodyParser(prompt); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	if prompt.Message == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "message is required")
+	}
+
+	prompt.OwnerID = owner
+
+	ctx := context.Context(c.Context())
+	if h.config.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, h.config.Timeout)
+		defer cancel()
+	}
+
+	result, err := h.promptUsecase.SendPrompt(ctx, prompt)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+// StreamPrompt is a function to relay prompt response as Server-Sent Events
+// The prompt is prepared before the stream starts, so invalid prompt is reported with its HTTP status,
+// the stream writer runs after the handler returns, so it owns the context of the upstream run
+func (h *promptHandler) StreamPrompt(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	prompt := new(request.Prompt)
+
+	if err := c.BodyParser(prompt); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	if prompt.Message == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "message is required")
+	}
+
+	prompt.OwnerID = owner
+
+	prepared, err := h.promptUsecase.PreparePrompt(c.Context(), prompt)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set(fiber.HeaderTransferEncoding, "chunked")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		err := h.promptUsecase.StreamPrompt(ctx, prepared, func(event *response.PromptEvent) error {
+			return writeEvent(w, event.Type, event)
+		})
+		if err != nil {
+			log.Warn(err)
+
+			_ = writeEvent(w, response.PromptEventError, &response.PromptEvent{
+				Type:  response.PromptEventError,
+				Error: toFiberError(err).Error(),
+			})
+		}
+	})
+
+	return nil
+}
+
+// SubmitPrompt is a function to start prompt run and return it before it is finished
+func (h *promptHandler) SubmitPrompt(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
 	prompt := new(request.Prompt)
 
 	if err := c.BodyParser(prompt); err != nil {
@@ -29,11 +146,91 @@ func (h *promptHandler) SendPrompt(c *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	result, err := h.promptUsecase.SendPrompt(c.Context(), prompt.Message)
+	if prompt.Message == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "message is required")
+	}
+
+	prompt.OwnerID = owner
+
+	result, err := h.promptUsecase.SubmitPrompt(c.Context(), prompt)
 	if err != nil {
 		log.Warn(err)
-		return fiber.ErrInternalServerError
+		return toFiberError(err)
+	}
+
+	c.Location(fmt.Sprintf("%s/%s", c.Path(), result.ID))
+
+	return c.Status(fiber.StatusAccepted).JSON(result)
+}
+
+// GetRun is a function to get status and output of submitted prompt run
+func (h *promptHandler) GetRun(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.promptUsecase.GetRun(c.Context(), c.Params("id"), owner)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
 	}
 
 	return c.JSON(result)
 }
+
+// CancelRun is a function to cancel run of thread and return it after it is finished
+func (h *promptHandler) CancelRun(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.promptUsecase.CancelRun(c.Context(), c.Params("thread_id"), c.Params("run_id"), owner)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *promptHandler) GetListMessage(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	option := new(request.ListMessage)
+
+	if err := c.QueryParser(option); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	option.ThreadID = c.Params("id")
+	option.OwnerID = owner
+
+	result, err := h.promptUsecase.GetListMessage(c.Context(), option)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+// writeEvent is a function to write Server-Sent Event and flush it to the client
+// It returns error when the client is disconnected
+func writeEvent(w *bufio.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,117 @@
+package httphandler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+type threadHandler struct {
+	threadUsecase usecases.ThreadUsecase
+}
+
+func NewThreadHandler(threadUsecase usecases.ThreadUsecase) ThreadHandler {
+	return &threadHandler{
+		threadUsecase: threadUsecase,
+	}
+}
+
+type ThreadHandler interface {
+	GetListThread(c *fiber.Ctx) error
+	GetThread(c *fiber.Ctx) error
+	UpdateThread(c *fiber.Ctx) error
+	DeleteThread(c *fiber.Ctx) error
+	GetFileContent(c *fiber.Ctx) error
+}
+
+func (h *threadHandler) GetListThread(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	option := new(request.ListThread)
+
+	if err := c.QueryParser(option); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	option.OwnerID = owner
+
+	result, err := h.threadUsecase.GetListThread(c.Context(), option)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *threadHandler) GetThread(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.threadUsecase.GetThread(c.Context(), c.Params("id"), owner)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *threadHandler) UpdateThread(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	thread := new(request.Thread)
+
+	if err := c.BodyParser(thread); err != nil {
+		log.Warn(err)
+		return fiber.ErrBadRequest
+	}
+
+	result, err := h.threadUsecase.UpdateThread(c.Context(), c.Params("id"), owner, thread)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.JSON(result)
+}
+
+func (h *threadHandler) DeleteThread(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.threadUsecase.DeleteThread(c.Context(), c.Params("id"), owner); err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetFileContent is a function to stream file of the owner's thread, e.g. chart made by code interpreter
+func (h *threadHandler) GetFileContent(c *fiber.Ctx) error {
+	owner, err := ownerID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.threadUsecase.GetFileContent(c.Context(), c.Params("id"), c.Params("file_id"), owner)
+	if err != nil {
+		log.Warn(err)
+		return toFiberError(err)
+	}
+
+	return sendFileContent(c, result)
+}
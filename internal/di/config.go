@@ -0,0 +1,102 @@
+package di
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// getEnvString is a function to get string config from environment variable
+// It will return the fallback value when the variable is empty
+func getEnvString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
+
+// getEnvInt is a function to get integer config from environment variable
+// It will return the fallback value when the variable is empty or invalid
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		log.Warnw("Invalid Config:", "key", key, "value", value)
+		return fallback
+	}
+
+	return result
+}
+
+// getEnvFloat is a function to get float config from environment variable
+// It will return the fallback value when the variable is empty or invalid
+func getEnvFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Warnw("Invalid Config:", "key", key, "value", value)
+		return fallback
+	}
+
+	return result
+}
+
+// getEnvDuration is a function to get duration config from environment variable, e.g. 500ms or 30s
+// It will return the fallback value when the variable is empty or invalid
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := time.ParseDuration(value)
+	if err != nil {
+		log.Warnw("Invalid Config:", "key", key, "value", value)
+		return fallback
+	}
+
+	return result
+}
+
+// getEnvBool is a function to get boolean config from environment variable, e.g. true or 1
+// It will return the fallback value when the variable is empty or invalid
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Warnw("Invalid Config:", "key", key, "value", value)
+		return fallback
+	}
+
+	return result
+}
+
+// getEnvList is a function to get comma separated list config from environment variable
+// Empty items are skipped, it will return nil when the variable is empty
+func getEnvList(key string) []string {
+	var result []string
+
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
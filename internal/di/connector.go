@@ -1,20 +1,104 @@
 package di
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/url"
 	"os"
+	"sync"
 
+	"github.com/gofiber/fiber/v2/log"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 )
 
+var (
+	connectorOnce     sync.Once
+	connectorInstance connector.Connector
+)
+
 // GetConnector is a function to get connector
+// The connector is shared so every usecase uses the same HTTP connection pool
 func GetConnector() connector.Connector {
-	return connector.NewConnector(
-		&connector.OpenAI{
-			BaseURL: os.Getenv("OPENAI_V1_BASE_URL"),
-			Header: map[string]string{
-				connector.HeaderAuthorization: fmt.Sprintf("%s %s", connector.BearerAuthType, os.Getenv("OPENAI_API_KEY")),
+	connectorOnce.Do(func() {
+		connectorInstance = connector.NewConnector(
+			&connector.OpenAI{
+				BaseURL: os.Getenv("OPENAI_V1_BASE_URL"),
+				Header: map[string]string{
+					connector.HeaderAuthorization: fmt.Sprintf("%s %s", connector.BearerAuthType, os.Getenv("OPENAI_API_KEY")),
+				},
+				Retry:           GetRetryPolicy(),
+				Timeout:         getEnvDuration("OPENAI_HTTP_TIMEOUT", connector.DefaultTimeout),
+				OverallTimeout:  getEnvDuration("OPENAI_HTTP_OVERALL_TIMEOUT", 0),
+				TransportConfig: GetTransportConfig(),
 			},
-		},
-	)
+		)
+	})
+
+	return connectorInstance
+}
+
+// GetRetryPolicy is a function to get retry policy of connector
+func GetRetryPolicy() *connector.RetryPolicy {
+	return &connector.RetryPolicy{
+		MaxAttempts: getEnvInt("OPENAI_RETRY_MAX_ATTEMPTS", connector.DefaultRetryMaxAttempts),
+		BaseDelay:   getEnvDuration("OPENAI_RETRY_BASE_DELAY", connector.DefaultRetryBaseDelay),
+		MaxDelay:    getEnvDuration("OPENAI_RETRY_MAX_DELAY", connector.DefaultRetryMaxDelay),
+		Jitter:      getEnvFloat("OPENAI_RETRY_JITTER", connector.DefaultRetryJitter),
+	}
+}
+
+// GetTransportConfig is a function to get HTTP transport config of connector
+// It will stop the service when proxy or TLS config is invalid
+func GetTransportConfig() *connector.TransportConfig {
+	config := &connector.TransportConfig{
+		MaxIdleConns:          getEnvInt("OPENAI_HTTP_MAX_IDLE_CONNS", connector.DefaultMaxIdleConns),
+		MaxIdleConnsPerHost:   getEnvInt("OPENAI_HTTP_MAX_IDLE_CONNS_PER_HOST", connector.DefaultMaxIdleConnsPerHost),
+		IdleConnTimeout:       getEnvDuration("OPENAI_HTTP_IDLE_CONN_TIMEOUT", connector.DefaultIdleConnTimeout),
+		DialTimeout:           getEnvDuration("OPENAI_HTTP_DIAL_TIMEOUT", connector.DefaultDialTimeout),
+		KeepAlive:             getEnvDuration("OPENAI_HTTP_KEEP_ALIVE", connector.DefaultKeepAlive),
+		TLSHandshakeTimeout:   getEnvDuration("OPENAI_HTTP_TLS_HANDSHAKE_TIMEOUT", connector.DefaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: getEnvDuration("OPENAI_HTTP_RESPONSE_HEADER_TIMEOUT", connector.DefaultResponseHeaderTimeout),
+		HTTP2ReadIdleTimeout:  getEnvDuration("OPENAI_HTTP2_READ_IDLE_TIMEOUT", connector.DefaultHTTP2ReadIdleTimeout),
+		HTTP2PingTimeout:      getEnvDuration("OPENAI_HTTP2_PING_TIMEOUT", connector.DefaultHTTP2PingTimeout),
+	}
+
+	if proxy := os.Getenv("OPENAI_HTTP_PROXY"); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			log.Fatalw("Invalid Config:", "key", "OPENAI_HTTP_PROXY", "error", err)
+		}
+
+		config.ProxyURL = proxyURL
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile := os.Getenv("OPENAI_TLS_CA_FILE"); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			log.Fatalw("Invalid Config:", "key", "OPENAI_TLS_CA_FILE", "error", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			log.Fatalw("Invalid Config:", "key", "OPENAI_TLS_CA_FILE", "error", "no certificate found")
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if os.Getenv("OPENAI_TLS_MIN_VERSION") == "1.3" {
+		tlsConfig.MinVersion = tls.VersionTLS13
+	}
+
+	config.TLSConfig = tlsConfig
+
+	return config
 }
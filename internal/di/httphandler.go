@@ -1,17 +1,128 @@
 package di
 
-import "github.com/yonisaka/assistant/internal/adapters/httphandler"
+import (
+	"encoding/base64"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/yonisaka/assistant/internal/adapters/httphandler"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+const (
+	// fileUploadPath is the full path of file upload route, it must follow the route in router
+	fileUploadPath = "/api/v1/files"
+
+	// promptBodyOverhead is the room for prompt fields other than the base64 encoded inline file
+	promptBodyOverhead = 1 << 20
+
+	// defaultPromptTimeout is below the common idle timeout of 60s of load balancer
+	defaultPromptTimeout = 55 * time.Second
+
+	defaultFileUploadMaxSize           = 512 << 20
+	defaultFileUploadAllowedExtensions = ".c,.cpp,.csv,.docx,.html,.java,.json,.md,.pdf,.php,.pptx,.py,.rb,.tex,.txt,.xlsx,.xml"
+)
+
+// GetAdminConfig is a function to get admin credential from environment variable, empty disables admin endpoints
+func GetAdminConfig() httphandler.AdminConfig {
+	return httphandler.AdminConfig{
+		Key: os.Getenv("ADMIN_API_KEY"),
+	}
+}
+
+// promptPaths are the full paths of routes that accept prompt, they must follow the routes in router
+var promptPaths = []string{"/api/v1/prompt", "/api/v1/prompt/stream", "/api/v1/runs"}
+
+// GetBodyLimitConfig is a function to get request body limit of endpoints other than file upload and prompt
+// from environment variable
+// File upload is skipped because it streams its own body with FILE_UPLOAD_MAX_SIZE,
+// prompt is skipped because it is limited by GetPromptBodyLimitConfig
+func GetBodyLimitConfig() httphandler.BodyLimitConfig {
+	return httphandler.BodyLimitConfig{
+		Limit: getEnvInt("HTTP_BODY_LIMIT", fiber.DefaultBodyLimit),
+		Next: func(c *fiber.Ctx) bool {
+			if c.Method() != fiber.MethodPost {
+				return false
+			}
+
+			return c.Path() == fileUploadPath || slices.Contains(promptPaths, c.Path())
+		},
+	}
+}
+
+// GetPromptBodyLimitConfig is a function to get request body limit of prompt endpoints
+// It fits inline file of usecases.MaxPromptFileSize, so larger file is rejected with the reason by the usecase
+func GetPromptBodyLimitConfig() httphandler.BodyLimitConfig {
+	return httphandler.BodyLimitConfig{
+		Limit: base64.StdEncoding.EncodedLen(usecases.MaxPromptFileSize) + promptBodyOverhead,
+	}
+}
 
 // GetFileHandler is a function to get http openAI handler
 func GetFileHandler() httphandler.FileHandler {
 	return httphandler.NewFileHandler(
 		GetFileUsecase(),
+		GetFileUploadConfig(),
+		GetFileSyncConfig(),
 	)
 }
 
+// GetFileSyncConfig is a function to get local folder synced into files of an assistant from environment variable
+func GetFileSyncConfig() httphandler.FileSyncConfig {
+	return httphandler.FileSyncConfig{
+		Dir:          os.Getenv("FILE_SYNC_DIR"),
+		AssistantID:  getEnvString("FILE_SYNC_ASSISTANT_ID", os.Getenv("OPENAI_ASSISTANT_ID")),
+		AssistantIDs: getEnvList("FILE_SYNC_ALLOWED_ASSISTANT_IDS"),
+	}
+}
+
+// GetFileUploadConfig is a function to get limits of uploaded file from environment variable
+// Extensions are normalized to lowercase with leading dot, * allows every extension
+func GetFileUploadConfig() httphandler.FileUploadConfig {
+	allowed := getEnvList("FILE_UPLOAD_ALLOWED_EXTENSIONS")
+	if allowed == nil {
+		allowed = strings.Split(defaultFileUploadAllowedExtensions, ",")
+	}
+
+	var extensions []string
+	for _, extension := range allowed {
+		if extension == "*" {
+			extensions = nil
+			break
+		}
+
+		extensions = append(extensions, "."+strings.TrimPrefix(strings.ToLower(extension), "."))
+	}
+
+	return httphandler.FileUploadConfig{
+		MaxSize:           int64(getEnvInt("FILE_UPLOAD_MAX_SIZE", defaultFileUploadMaxSize)),
+		AllowedExtensions: extensions,
+	}
+}
+
 // GetPromptHandler is a function to get http openAI handler
 func GetPromptHandler() httphandler.PromptHandler {
 	return httphandler.NewPromptHandler(
 		GetPromptUsecase(),
+		httphandler.PromptConfig{
+			Timeout: getEnvDuration("PROMPT_TIMEOUT", defaultPromptTimeout),
+		},
+	)
+}
+
+// GetThreadHandler is a function to get http thread handler
+func GetThreadHandler() httphandler.ThreadHandler {
+	return httphandler.NewThreadHandler(
+		GetThreadUsecase(),
+	)
+}
+
+// GetAssistantHandler is a function to get http openAI handler
+func GetAssistantHandler() httphandler.AssistantHandler {
+	return httphandler.NewAssistantHandler(
+		GetAssistantUsecase(),
 	)
 }
@@ -0,0 +1,88 @@
+package di
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	profileRegistryOnce     sync.Once
+	profileRegistryInstance usecases.ProfileRegistry
+)
+
+var ErrProfileConfigFormat = errors.New("profile config must be a .yaml, .yml or .json file")
+
+// profileConfig is a struct of assistant profiles config file
+type profileConfig struct {
+	Profiles []usecases.Profile `json:"profiles" yaml:"profiles"`
+}
+
+// GetProfileRegistry is a function to get registry of assistant profiles loaded from PROFILES_PATH
+// The profiles are validated at startup, so invalid config stops the app instead of failing the prompt
+func GetProfileRegistry() usecases.ProfileRegistry {
+	profileRegistryOnce.Do(func() {
+		path := getEnvString("PROFILES_PATH", "")
+
+		profiles, err := loadProfiles(path)
+		if err != nil {
+			log.Fatalw("Profile Load Failed:", "path", path, "error", err)
+		}
+
+		profileRegistry, err := usecases.NewProfileRegistry(profiles, GetToolRegistry())
+		if err != nil {
+			log.Fatalw("Profile Load Failed:", "path", path, "error", err)
+		}
+
+		log.Infow("Profile Loaded:", "path", path, "count", len(profiles))
+
+		profileRegistryInstance = profileRegistry
+	})
+
+	return profileRegistryInstance
+}
+
+// loadProfiles is a function to read profiles from YAML or JSON file, unknown field is rejected
+// It will return no profile when the path is empty
+func loadProfiles(path string) ([]usecases.Profile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config profileConfig
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		err = decoder.Decode(&config)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(&config)
+	default:
+		return nil, ErrProfileConfigFormat
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile config: %w", err)
+	}
+
+	return config.Profiles, nil
+}
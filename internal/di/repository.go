@@ -0,0 +1,43 @@
+package di
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/storage"
+)
+
+const (
+	StorageMemory = "memory"
+	StorageBolt   = "bolt"
+
+	defaultStoragePath = "assistant.db"
+)
+
+var (
+	conversationRepositoryOnce     sync.Once
+	conversationRepositoryInstance repository.ConversationRepository
+)
+
+// GetConversationRepository is a function to get conversation repository
+// The storage is selected by STORAGE_DRIVER, BoltDB file is used by default so conversations survive restart
+func GetConversationRepository() repository.ConversationRepository {
+	conversationRepositoryOnce.Do(func() {
+		switch driver := getEnvString("STORAGE_DRIVER", StorageBolt); driver {
+		case StorageMemory:
+			conversationRepositoryInstance = storage.NewMemoryConversationRepository()
+		case StorageBolt:
+			conversationRepository, err := storage.NewBoltConversationRepository(getEnvString("STORAGE_PATH", defaultStoragePath))
+			if err != nil {
+				log.Fatalw("Storage Open Failed:", "driver", driver, "error", err)
+			}
+
+			conversationRepositoryInstance = conversationRepository
+		default:
+			log.Fatalw("Invalid Config:", "key", "STORAGE_DRIVER", "value", driver)
+		}
+	})
+
+	return conversationRepositoryInstance
+}
@@ -2,16 +2,48 @@ package di
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/yonisaka/assistant/internal/adapters/httphandler"
 )
 
 func GetRouter(app *fiber.App) {
 	// API Group
 	api := app.Group("/api")
-	v1 := api.Group("/v1")
+	v1 := api.Group("/v1", httphandler.NewBodyLimit(GetBodyLimitConfig()))
+
+	// Files and assistants are shared by every owner in the OpenAI account, owner downloads file of its thread instead,
+	// the synced folder replaces files of the assistant every owner talks to
+	adminGuard := httphandler.NewAdminGuard(GetAdminConfig())
 
 	fileHandler := GetFileHandler()
-	v1.Get("/files", fileHandler.GetListFile)
+	v1.Get("/files", adminGuard, fileHandler.GetListFile)
+	v1.Post("/files", adminGuard, fileHandler.UploadFile)
+	v1.Get("/files/:id", adminGuard, fileHandler.GetFile)
+	v1.Delete("/files/:id", adminGuard, fileHandler.DeleteFile)
+	v1.Get("/files/:id/content", adminGuard, fileHandler.GetFileContent)
+	v1.Post("/admin/files/sync", adminGuard, fileHandler.SyncFiles)
+
+	assistantHandler := GetAssistantHandler()
+	v1.Post("/assistants", adminGuard, assistantHandler.CreateAssistant)
+	v1.Get("/assistants", adminGuard, assistantHandler.GetListAssistant)
+	v1.Get("/assistants/:id", adminGuard, assistantHandler.GetAssistant)
+	v1.Patch("/assistants/:id", adminGuard, assistantHandler.UpdateAssistant)
+	v1.Delete("/assistants/:id", adminGuard, assistantHandler.DeleteAssistant)
+
+	// Prompt carries inline file, so it has its own body limit, see GetPromptBodyLimitConfig
+	promptBodyLimit := httphandler.NewBodyLimit(GetPromptBodyLimitConfig())
 
 	promptHandler := GetPromptHandler()
-	v1.Post("/prompt", promptHandler.SendPrompt)
+	v1.Post("/prompt", promptBodyLimit, promptHandler.SendPrompt)
+	v1.Post("/prompt/stream", promptBodyLimit, promptHandler.StreamPrompt)
+	v1.Post("/runs", promptBodyLimit, promptHandler.SubmitPrompt)
+	v1.Get("/runs/:id", promptHandler.GetRun)
+	v1.Post("/threads/:thread_id/runs/:run_id/cancel", promptHandler.CancelRun)
+	v1.Get("/threads/:id/messages", promptHandler.GetListMessage)
+
+	threadHandler := GetThreadHandler()
+	v1.Get("/threads", threadHandler.GetListThread)
+	v1.Get("/threads/:id", threadHandler.GetThread)
+	v1.Patch("/threads/:id", threadHandler.UpdateThread)
+	v1.Delete("/threads/:id", threadHandler.DeleteThread)
+	v1.Get("/threads/:id/files/:file_id/content", threadHandler.GetFileContent)
 }
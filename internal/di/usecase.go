@@ -1,11 +1,29 @@
 package di
 
-import "github.com/yonisaka/assistant/internal/usecases"
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+var (
+	toolRegistryOnce     sync.Once
+	toolRegistryInstance usecases.ToolRegistry
+)
 
 // GetFileUsecase is a function to get usecase
 func GetFileUsecase() usecases.FileUsecase {
 	return usecases.NewFileUsecase(
 		GetConnector(),
+		GetAssistantUsecase(),
+	)
+}
+
+// GetAssistantUsecase is a function to get usecase
+func GetAssistantUsecase() usecases.AssistantUsecase {
+	return usecases.NewAssistantUsecase(
+		GetConnector(),
 	)
 }
 
@@ -13,5 +31,61 @@ func GetFileUsecase() usecases.FileUsecase {
 func GetPromptUsecase() usecases.PromptUsecase {
 	return usecases.NewPromptUsecase(
 		GetConnector(),
+		GetConversationRepository(),
+		GetToolRegistry(),
+		GetPollStrategy(),
+		GetRunPolicy(),
+		GetFileUsecase(),
+	)
+}
+
+// GetThreadUsecase is a function to get usecase
+func GetThreadUsecase() usecases.ThreadUsecase {
+	return usecases.NewThreadUsecase(
+		GetConnector(),
+		GetConversationRepository(),
+		GetFileUsecase(),
 	)
 }
+
+// GetToolRegistry is a function to get registry of tools that can be called by the assistant
+// Register new tool here, the assistant must have the same function definition to call it
+func GetToolRegistry() usecases.ToolRegistry {
+	toolRegistryOnce.Do(func() {
+		toolRegistryInstance = usecases.NewToolRegistry()
+
+		tools := []usecases.Tool{
+			usecases.CurrentTimeTool(),
+		}
+
+		for _, tool := range tools {
+			if err := toolRegistryInstance.Register(tool); err != nil {
+				log.Fatalw("Tool Register Failed:", "name", tool.Name, "error", err)
+			}
+		}
+	})
+
+	return toolRegistryInstance
+}
+
+// GetPollStrategy is a function to get strategy of polling run status
+func GetPollStrategy() usecases.PollStrategy {
+	return usecases.NewBackoffPollStrategy(usecases.PollConfig{
+		InitialInterval: getEnvDuration("RUN_POLL_INITIAL_INTERVAL", usecases.DefaultPollInitialInterval),
+		Multiplier:      getEnvFloat("RUN_POLL_MULTIPLIER", usecases.DefaultPollMultiplier),
+		MaxInterval:     getEnvDuration("RUN_POLL_MAX_INTERVAL", usecases.DefaultPollMaxInterval),
+		MaxWait:         getEnvDuration("RUN_POLL_MAX_WAIT", usecases.DefaultPollMaxWait),
+	})
+}
+
+// GetRunPolicy is a function to get allow-list of run options a prompt can override
+func GetRunPolicy() usecases.RunPolicy {
+	return usecases.NewRunPolicy(usecases.RunPolicyConfig{
+		DefaultAssistantID: getEnvString("OPENAI_ASSISTANT_ID", ""),
+		AssistantIDs:       getEnvList("RUN_ALLOWED_ASSISTANT_IDS"),
+		Models:             getEnvList("RUN_ALLOWED_MODELS"),
+		Tools:              getEnvList("RUN_ALLOWED_TOOLS"),
+		AllowInstructions:  getEnvBool("RUN_ALLOW_INSTRUCTIONS", false),
+		FileIDs:            getEnvList("RUN_ALLOWED_FILE_IDS"),
+	}, GetToolRegistry(), GetProfileRegistry())
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+//go:generate rm -f ./conversation_mock.go
+//go:generate mockgen -destination conversation_mock.go -package repository -mock_names ConversationRepository=GoMockConversationRepository -source conversation.go
+
+// Conversation is a struct of conversation that maps a client session to OpenAI thread
+type Conversation struct {
+	ID          string            `json:"id"`
+	OwnerID     string            `json:"owner_id"`
+	ThreadID    string            `json:"thread_id"`
+	AssistantID string            `json:"assistant_id"`
+	Title       string            `json:"title"`
+	CreatedAt   int64             `json:"created_at"`
+	UpdatedAt   int64             `json:"updated_at"`
+	Metadata    map[string]string `json:"metadata"`
+	// Profile is the profile the conversation is started with, its later prompts run with it too
+	Profile string `json:"profile,omitempty"`
+	// FileIDs are files uploaded with prompts of the conversation, its later prompts can attach them again
+	FileIDs []string `json:"file_ids,omitempty"`
+	// Tags and Pinned are set by the owner to organize conversations
+	Tags   []string `json:"tags,omitempty"`
+	Pinned bool     `json:"pinned"`
+}
+
+// ConversationRepository is an interface to store conversation
+// CreatedAt and UpdatedAt are set by the repository
+// Modify changes the stored conversation within one transaction, so concurrent change of other fields is kept,
+// ID, OwnerID and ThreadID cannot be changed by it
+type ConversationRepository interface {
+	Create(ctx context.Context, conversation *Conversation) error
+	Get(ctx context.Context, id string) (*Conversation, error)
+	GetByThreadID(ctx context.Context, threadID string) (*Conversation, error)
+	List(ctx context.Context, ownerID string) ([]Conversation, error)
+	Update(ctx context.Context, conversation *Conversation) error
+	Modify(ctx context.Context, id string, change func(conversation *Conversation)) (*Conversation, error)
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+var (
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrConversationExists   = errors.New("conversation already exists")
+)
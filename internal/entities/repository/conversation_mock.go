@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: conversation.go
+//
+// Generated by this command:
+//
+//	mockgen -destination conversation_mock.go -package repository -mock_names ConversationRepository=GoMockConversationRepository -source conversation.go
+//
+
+// Package repository is a generated GoMock package.
+package repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// GoMockConversationRepository is a mock of ConversationRepository interface.
+type GoMockConversationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *GoMockConversationRepositoryMockRecorder
+}
+
+// GoMockConversationRepositoryMockRecorder is the mock recorder for GoMockConversationRepository.
+type GoMockConversationRepositoryMockRecorder struct {
+	mock *GoMockConversationRepository
+}
+
+// NewGoMockConversationRepository creates a new mock instance.
+func NewGoMockConversationRepository(ctrl *gomock.Controller) *GoMockConversationRepository {
+	mock := &GoMockConversationRepository{ctrl: ctrl}
+	mock.recorder = &GoMockConversationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *GoMockConversationRepository) EXPECT() *GoMockConversationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *GoMockConversationRepository) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *GoMockConversationRepositoryMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*GoMockConversationRepository)(nil).Close))
+}
+
+// Create mocks base method.
+func (m *GoMockConversationRepository) Create(ctx context.Context, conversation *Conversation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, conversation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *GoMockConversationRepositoryMockRecorder) Create(ctx, conversation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*GoMockConversationRepository)(nil).Create), ctx, conversation)
+}
+
+// Delete mocks base method.
+func (m *GoMockConversationRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *GoMockConversationRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*GoMockConversationRepository)(nil).Delete), ctx, id)
+}
+
+// Get mocks base method.
+func (m *GoMockConversationRepository) Get(ctx context.Context, id string) (*Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *GoMockConversationRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*GoMockConversationRepository)(nil).Get), ctx, id)
+}
+
+// GetByThreadID mocks base method.
+func (m *GoMockConversationRepository) GetByThreadID(ctx context.Context, threadID string) (*Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByThreadID", ctx, threadID)
+	ret0, _ := ret[0].(*Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByThreadID indicates an expected call of GetByThreadID.
+func (mr *GoMockConversationRepositoryMockRecorder) GetByThreadID(ctx, threadID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByThreadID", reflect.TypeOf((*GoMockConversationRepository)(nil).GetByThreadID), ctx, threadID)
+}
+
+// List mocks base method.
+func (m *GoMockConversationRepository) List(ctx context.Context, ownerID string) ([]Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, ownerID)
+	ret0, _ := ret[0].([]Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *GoMockConversationRepositoryMockRecorder) List(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*GoMockConversationRepository)(nil).List), ctx, ownerID)
+}
+
+// Modify mocks base method.
+func (m *GoMockConversationRepository) Modify(ctx context.Context, id string, change func(*Conversation)) (*Conversation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Modify", ctx, id, change)
+	ret0, _ := ret[0].(*Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Modify indicates an expected call of Modify.
+func (mr *GoMockConversationRepositoryMockRecorder) Modify(ctx, id, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Modify", reflect.TypeOf((*GoMockConversationRepository)(nil).Modify), ctx, id, change)
+}
+
+// Update mocks base method.
+func (m *GoMockConversationRepository) Update(ctx context.Context, conversation *Conversation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, conversation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *GoMockConversationRepositoryMockRecorder) Update(ctx, conversation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*GoMockConversationRepository)(nil).Update), ctx, conversation)
+}
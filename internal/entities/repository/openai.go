@@ -1,5 +1,7 @@
 package repository
 
+import "encoding/json"
+
 // File is a struct of file from OpenAI API
 type File struct {
 	Object        string      `json:"object"`
@@ -19,20 +21,80 @@ type Message struct {
 	ThreadID    string           `json:"thread_id"`
 	Role        string           `json:"role"`
 	Content     []ContentMessage `json:"content"`
-	FileIDS     []interface{}    `json:"file_ids"`
+	FileIDS     []string         `json:"file_ids"`
 	AssistantID string           `json:"assistant_id"`
 	RunID       string           `json:"run_id"`
 	Metadata    interface{}      `json:"metadata"`
 }
 
+const (
+	ContentTypeText      = "text"
+	ContentTypeImageFile = "image_file"
+	ContentTypeImageURL  = "image_url"
+
+	AnnotationTypeFileCitation = "file_citation"
+	AnnotationTypeFilePath     = "file_path"
+)
+
+// ContentMessage is a struct of a part of message content, it is a tagged union by Type
+// Only the field named by Type is set, e.g. ImageFile for image_file
 type ContentMessage struct {
-	Type string      `json:"type"`
-	Text TextContent `json:"text"`
+	Type      string            `json:"type"`
+	Text      *TextContent      `json:"text,omitempty"`
+	ImageFile *ImageFileContent `json:"image_file,omitempty"`
+	ImageURL  *ImageURLContent  `json:"image_url,omitempty"`
 }
 
+// TextContent is a struct of text part of message
+// Footnotes are not sent by OpenAI, they are resolved from citations and marked as [n] in Value
 type TextContent struct {
-	Value       string        `json:"value"`
-	Annotations []interface{} `json:"annotations"`
+	Value       string       `json:"value"`
+	Annotations []Annotation `json:"annotations"`
+	Footnotes   []Footnote   `json:"footnotes,omitempty"`
+}
+
+// ImageFileContent is a struct of image part of message that is stored as file, e.g. chart of code interpreter
+// URL is not sent by OpenAI, it is the link to download the file from this API
+type ImageFileContent struct {
+	FileID string `json:"file_id"`
+	Detail string `json:"detail,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// ImageURLContent is a struct of image part of message that is linked from the web
+type ImageURLContent struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Annotation is a struct of annotation of text part, it is a tagged union by Type
+// Text is the annotated part of the value, from StartIndex to EndIndex
+type Annotation struct {
+	Type         string        `json:"type"`
+	Text         string        `json:"text"`
+	StartIndex   int           `json:"start_index"`
+	EndIndex     int           `json:"end_index"`
+	FileCitation *FileCitation `json:"file_citation,omitempty"`
+	FilePath     *FilePath     `json:"file_path,omitempty"`
+}
+
+// FileCitation is a struct of quote from file that is read by retrieval
+type FileCitation struct {
+	FileID string `json:"file_id"`
+	Quote  string `json:"quote"`
+}
+
+// FilePath is a struct of file that is generated by code interpreter
+type FilePath struct {
+	FileID string `json:"file_id"`
+}
+
+// Footnote is a struct of citation resolved from file_citation annotation, Number is its marker in text
+type Footnote struct {
+	Number int    `json:"number"`
+	FileID string `json:"file_id"`
+	Quote  string `json:"quote"`
+	URL    string `json:"url"`
 }
 
 type Thread struct {
@@ -41,3 +103,30 @@ type Thread struct {
 	CreatedAt int64       `json:"created_at"`
 	Metadata  interface{} `json:"metadata"`
 }
+
+// Assistant is a struct of assistant from OpenAI API
+type Assistant struct {
+	ID           string            `json:"id"`
+	Object       string            `json:"object"`
+	CreatedAt    int64             `json:"created_at"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Model        string            `json:"model"`
+	Instructions string            `json:"instructions"`
+	Tools        []AssistantTool   `json:"tools"`
+	FileIDs      []string          `json:"file_ids"`
+	Metadata     map[string]string `json:"metadata"`
+}
+
+// AssistantTool is a struct of tool enabled on assistant or overridden by run, function is set only for function tool
+type AssistantTool struct {
+	Type     string             `json:"type"`
+	Function *AssistantFunction `json:"function,omitempty"`
+}
+
+// AssistantFunction is a struct of function the assistant can call
+type AssistantFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
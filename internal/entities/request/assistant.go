@@ -0,0 +1,15 @@
+package request
+
+import "github.com/yonisaka/assistant/internal/entities/repository"
+
+// Assistant is a struct of assistant request from client
+// Model is required to create assistant, field that is not sent is not changed on update
+type Assistant struct {
+	Model        string                      `json:"model"`
+	Name         *string                     `json:"name"`
+	Description  *string                     `json:"description"`
+	Instructions *string                     `json:"instructions"`
+	Tools        *[]repository.AssistantTool `json:"tools"`
+	FileIDs      *[]string                   `json:"file_ids"`
+	Metadata     map[string]string           `json:"metadata"`
+}
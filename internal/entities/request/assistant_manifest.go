@@ -0,0 +1,21 @@
+package request
+
+import "github.com/yonisaka/assistant/internal/entities/repository"
+
+// AssistantManifest is a struct of desired assistants to be synced with OpenAI
+type AssistantManifest struct {
+	Assistants []AssistantSpec `json:"assistants"`
+}
+
+// AssistantSpec is a struct of desired assistant, it is matched with assistant in OpenAI by its key
+// so the assistant can be renamed safely, every field is managed and empty field is synced as empty
+type AssistantSpec struct {
+	Key          string                     `json:"key"`
+	Name         string                     `json:"name"`
+	Description  string                     `json:"description"`
+	Model        string                     `json:"model"`
+	Instructions string                     `json:"instructions"`
+	Tools        []repository.AssistantTool `json:"tools"`
+	FileIDs      []string                   `json:"file_ids"`
+	Metadata     map[string]string          `json:"metadata"`
+}
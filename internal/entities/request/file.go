@@ -0,0 +1,30 @@
+package request
+
+import "io"
+
+// UploadFile is a struct of file uploaded by client
+// Reader is streamed to OpenAI API, Size is its length or zero when it is unknown
+type UploadFile struct {
+	Purpose     string
+	Filename    string
+	ContentType string
+	Size        int64
+	Reader      io.Reader
+}
+
+// ListFile is a struct of list file request from client
+// All pages through every file starting from After, Limit is then the size of each page
+type ListFile struct {
+	ListOption
+	Purpose string `query:"purpose"`
+	All     bool   `query:"all"`
+}
+
+// FileSync is a struct of request to sync local folder into files of an assistant
+// Dir and Extensions come from config instead of client, empty Extensions syncs every file
+type FileSync struct {
+	AssistantID string   `json:"assistant_id"`
+	DryRun      bool     `json:"dry_run"`
+	Dir         string   `json:"-"`
+	Extensions  []string `json:"-"`
+}
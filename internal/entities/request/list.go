@@ -0,0 +1,10 @@
+package request
+
+// ListOption is a struct of cursor pagination of list request from client
+// Limit zero uses the default of OpenAI API, Order is asc or desc
+type ListOption struct {
+	Limit  int    `query:"limit"`
+	After  string `query:"after"`
+	Before string `query:"before"`
+	Order  string `query:"order"`
+}
@@ -1,5 +1,35 @@
 package request
 
+// Prompt is a struct of prompt request from client
+// Profile chooses a named assistant setup, run options are optional,
+// they override the assistant or the profile only when allowed by config
 type Prompt struct {
-	Message string `json:"message" validate:"required"`
+	Message                string            `json:"message" validate:"required"`
+	ConversationID         string            `json:"conversation_id"`
+	ThreadID               string            `json:"thread_id"`
+	OwnerID                string            `json:"-"`
+	Profile                string            `json:"profile"`
+	AssistantID            string            `json:"assistant_id"`
+	Model                  string            `json:"model"`
+	Instructions           string            `json:"instructions"`
+	AdditionalInstructions string            `json:"additional_instructions"`
+	Tools                  []string          `json:"tools"`
+	Metadata               map[string]string `json:"metadata"`
+	// FileIDs are existing files attached to the message, File is uploaded first then attached too
+	FileIDs []string    `json:"file_ids"`
+	File    *PromptFile `json:"file"`
+}
+
+// PromptFile is a struct of file uploaded inline with prompt, Data is base64 encoded in JSON
+type PromptFile struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// ListMessage is a struct of list message request of the owner's thread from client
+type ListMessage struct {
+	ListOption
+	ThreadID string `query:"-"`
+	OwnerID  string `query:"-"`
 }
@@ -0,0 +1,14 @@
+package request
+
+// Thread is a struct of thread metadata request from client, field that is not sent is not changed
+type Thread struct {
+	Title  *string   `json:"title"`
+	Tags   *[]string `json:"tags"`
+	Pinned *bool     `json:"pinned"`
+}
+
+// ListThread is a struct of list thread request of the owner from client, Tag filters threads that have it
+type ListThread struct {
+	Tag     string `query:"tag"`
+	OwnerID string `query:"-"`
+}
@@ -0,0 +1,51 @@
+package response
+
+import "github.com/yonisaka/assistant/internal/entities/request"
+
+const (
+	AssistantChangeCreate = "create"
+	AssistantChangeUpdate = "update"
+	AssistantChangeDelete = "delete"
+	AssistantChangeNoOp   = "no-op"
+)
+
+// AssistantSyncPlan is a struct of changes to sync assistant manifest with OpenAI
+type AssistantSyncPlan struct {
+	Changes []AssistantChange `json:"changes"`
+}
+
+// AssistantChange is a struct of change of a single assistant
+// AssistantID is empty for assistant to be created until the plan is applied
+type AssistantChange struct {
+	Action      string               `json:"action"`
+	Key         string               `json:"key"`
+	AssistantID string               `json:"assistant_id,omitempty"`
+	Name        string               `json:"name"`
+	Diffs       []AssistantFieldDiff `json:"diffs,omitempty"`
+	Assistant   *request.Assistant   `json:"-"`
+}
+
+// AssistantFieldDiff is a struct of field that is different between OpenAI and the manifest
+type AssistantFieldDiff struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Count is a function to count changes of the action
+func (p *AssistantSyncPlan) Count(action string) int {
+	count := 0
+
+	for _, change := range p.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
+
+// HasChanges is a function to check if applying the plan will change anything
+func (p *AssistantSyncPlan) HasChanges() bool {
+	return len(p.Changes) > p.Count(AssistantChangeNoOp)
+}
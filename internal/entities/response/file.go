@@ -0,0 +1,53 @@
+package response
+
+import "io"
+
+// FileContent is a struct of raw content of file streamed to client
+// ContentLength is -1 when it is unknown, the caller must close the Body
+type FileContent struct {
+	Body               io.ReadCloser
+	ContentType        string
+	ContentDisposition string
+	ContentLength      int64
+}
+
+const (
+	FileSyncUpload    = "upload"
+	FileSyncReplace   = "replace"
+	FileSyncDelete    = "delete"
+	FileSyncUnchanged = "unchanged"
+)
+
+// FileSyncReport is a struct of result of syncing local folder into files of an assistant
+// FileIDs are the files of the assistant after the sync, they are not set on dry run
+type FileSyncReport struct {
+	AssistantID string           `json:"assistant_id"`
+	DryRun      bool             `json:"dry_run"`
+	Changes     []FileSyncChange `json:"changes"`
+	FileIDs     []string         `json:"file_ids,omitempty"`
+}
+
+// FileSyncChange is a struct of change of a single file
+// FileID is empty for file to be uploaded until it is applied, PreviousFileID is the file that is replaced or deleted
+type FileSyncChange struct {
+	Action         string `json:"action"`
+	Path           string `json:"path"`
+	FileID         string `json:"file_id,omitempty"`
+	PreviousFileID string `json:"previous_file_id,omitempty"`
+	SHA256         string `json:"sha256,omitempty"`
+	Bytes          int64  `json:"bytes,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// Count is a function to count changes of the action
+func (r *FileSyncReport) Count(action string) int {
+	count := 0
+
+	for _, change := range r.Changes {
+		if change.Action == action {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,11 @@
+package response
+
+// List is a struct of a page of list sent to client
+// LastID is the cursor to request the next page when HasMore is true,
+// FirstID is the cursor to request the previous page and it is only set when known
+type List[T any] struct {
+	Data    []T    `json:"data"`
+	HasMore bool   `json:"has_more"`
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id"`
+}
@@ -0,0 +1,77 @@
+package response
+
+import "github.com/yonisaka/assistant/internal/entities/repository"
+
+const (
+	PromptEventStatus  = "status"
+	PromptEventDelta   = "delta"
+	PromptEventMessage = "message"
+	PromptEventDone    = "done"
+	PromptEventError   = "error"
+)
+
+// PromptEvent is a struct of streamed prompt event sent to client
+type PromptEvent struct {
+	Type           string              `json:"type"`
+	ConversationID string              `json:"conversation_id,omitempty"`
+	ThreadID       string              `json:"thread_id,omitempty"`
+	RunID          string              `json:"run_id,omitempty"`
+	Status         string              `json:"status,omitempty"`
+	Text           string              `json:"text,omitempty"`
+	Message        *repository.Message `json:"message,omitempty"`
+	Attachments    []Attachment        `json:"attachments,omitempty"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// Attachment is a struct of file attached to the prompt message, Uploaded is true for file uploaded with the prompt
+type Attachment struct {
+	FileID   string `json:"file_id"`
+	Filename string `json:"filename"`
+	Bytes    int64  `json:"bytes"`
+	Uploaded bool   `json:"uploaded"`
+}
+
+// Prompt is a struct of prompt response sent to client
+// ConversationID or ThreadID is used to continue the conversation
+// Messages are all assistant messages created by the run in the order they are created
+type Prompt struct {
+	ConversationID string               `json:"conversation_id"`
+	ThreadID       string               `json:"thread_id"`
+	RunID          string               `json:"run_id"`
+	Messages       []repository.Message `json:"messages"`
+	Attachments    []Attachment         `json:"attachments,omitempty"`
+}
+
+// Run is a struct of asynchronous prompt run sent to client
+// Messages are set when the run is completed, Error is set when the run ends without completing
+type Run struct {
+	ID             string               `json:"id"`
+	ConversationID string               `json:"conversation_id"`
+	ThreadID       string               `json:"thread_id"`
+	Status         string               `json:"status"`
+	Messages       []repository.Message `json:"messages,omitempty"`
+	Attachments    []Attachment         `json:"attachments,omitempty"`
+	Error          string               `json:"error,omitempty"`
+	CreatedAt      int64                `json:"created_at"`
+	FinishedAt     int64                `json:"finished_at,omitempty"`
+}
+
+// Message is a struct of thread message sent to client
+// Text joins every text part with annotations resolved, Footnotes are the citations marked as [n] in Text
+type Message struct {
+	ID          string                `json:"id"`
+	Role        string                `json:"role"`
+	Text        string                `json:"text"`
+	Footnotes   []repository.Footnote `json:"footnotes,omitempty"`
+	Images      []MessageFile         `json:"images,omitempty"`
+	Attachments []MessageFile         `json:"attachments,omitempty"`
+	RunID       string                `json:"run_id,omitempty"`
+	CreatedAt   int64                 `json:"created_at"`
+}
+
+// MessageFile is a struct of file in the message, URL is the link to download it
+// Image linked from the web has URL only
+type MessageFile struct {
+	FileID string `json:"file_id,omitempty"`
+	URL    string `json:"url"`
+}
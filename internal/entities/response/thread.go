@@ -0,0 +1,14 @@
+package response
+
+// Thread is a struct of the owner's thread sent to client
+// ID is the thread ID of OpenAI, it is also used to continue the conversation
+type Thread struct {
+	ID             string   `json:"id"`
+	ConversationID string   `json:"conversation_id"`
+	AssistantID    string   `json:"assistant_id,omitempty"`
+	Title          string   `json:"title"`
+	Tags           []string `json:"tags"`
+	Pinned         bool     `json:"pinned"`
+	CreatedAt      int64    `json:"created_at"`
+	UpdatedAt      int64    `json:"updated_at"`
+}
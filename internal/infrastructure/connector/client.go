@@ -0,0 +1,172 @@
+package connector
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"golang.org/x/net/http2"
+)
+
+const (
+	DefaultTimeout               = 60 * time.Second
+	DefaultMaxIdleConns          = 100
+	DefaultMaxIdleConnsPerHost   = 20
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultDialTimeout           = 10 * time.Second
+	DefaultKeepAlive             = 30 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 60 * time.Second
+	DefaultHTTP2ReadIdleTimeout  = 30 * time.Second
+	DefaultHTTP2PingTimeout      = 15 * time.Second
+)
+
+// TransportConfig is a struct to set HTTP transport used to call OpenAI API
+// Zero value of each field means the default value will be used
+type TransportConfig struct {
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	// ProxyURL is the HTTP proxy, proxy from environment variable is used when it is nil
+	ProxyURL  *url.URL
+	TLSConfig *tls.Config
+	// HTTP2ReadIdleTimeout is the idle time after which a health check ping is sent on HTTP/2 connection
+	HTTP2ReadIdleTimeout time.Duration
+	// HTTP2PingTimeout is the time after which HTTP/2 connection is closed if ping is not answered
+	HTTP2PingTimeout time.Duration
+}
+
+// DefaultTransportConfig is a function to get recommended transport config for OpenAI API
+func DefaultTransportConfig() *TransportConfig {
+	return &TransportConfig{
+		MaxIdleConns:          DefaultMaxIdleConns,
+		MaxIdleConnsPerHost:   DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:       DefaultIdleConnTimeout,
+		DialTimeout:           DefaultDialTimeout,
+		KeepAlive:             DefaultKeepAlive,
+		TLSHandshakeTimeout:   DefaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: DefaultResponseHeaderTimeout,
+		HTTP2ReadIdleTimeout:  DefaultHTTP2ReadIdleTimeout,
+		HTTP2PingTimeout:      DefaultHTTP2PingTimeout,
+	}
+}
+
+// newHTTPClient is a function to create HTTP client from OpenAI config
+// Client from config is used as is, otherwise a client is built from Transport or TransportConfig
+func newHTTPClient(openai *OpenAI) *http.Client {
+	if openai.Client != nil {
+		return openai.Client
+	}
+
+	transport := openai.Transport
+	if transport == nil {
+		transport = newTransport(openai.TransportConfig)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   openai.Timeout,
+	}
+}
+
+// newStreamClient is a function to create HTTP client for long-lived streaming response or upload
+// It shares the transport of the given client but has no timeout, the stream is bounded by its context
+func newStreamClient(client *http.Client) *http.Client {
+	streamClient := *client
+	streamClient.Timeout = 0
+
+	return &streamClient
+}
+
+// newTransport is a function to create HTTP transport from transport config
+func newTransport(config *TransportConfig) *http.Transport {
+	if config == nil {
+		config = DefaultTransportConfig()
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config.ProxyURL != nil {
+		proxy = http.ProxyURL(config.ProxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   durationOrDefault(config.DialTimeout, DefaultDialTimeout),
+		KeepAlive: durationOrDefault(config.KeepAlive, DefaultKeepAlive),
+	}
+
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          intOrDefault(config.MaxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost:   intOrDefault(config.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost),
+		IdleConnTimeout:       durationOrDefault(config.IdleConnTimeout, DefaultIdleConnTimeout),
+		TLSHandshakeTimeout:   durationOrDefault(config.TLSHandshakeTimeout, DefaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: durationOrDefault(config.ResponseHeaderTimeout, DefaultResponseHeaderTimeout),
+		ExpectContinueTimeout: time.Second,
+	}
+
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig.Clone()
+	}
+
+	// Tune HTTP/2 keep-alive so dead connection is detected instead of hanging the request
+	http2Transport, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		log.Warnw("HTTP/2 Not Configured:", "error", err)
+		return transport
+	}
+
+	http2Transport.ReadIdleTimeout = durationOrDefault(config.HTTP2ReadIdleTimeout, DefaultHTTP2ReadIdleTimeout)
+	http2Transport.PingTimeout = durationOrDefault(config.HTTP2PingTimeout, DefaultHTTP2PingTimeout)
+
+	return transport
+}
+
+// withOverallTimeout is a function to limit the whole request, including all retries, with OverallTimeout
+// Streaming request or upload is not limited because the body is read as long as its context is alive
+func (c *connector) withOverallTimeout(ctx context.Context, client *http.Client) (context.Context, context.CancelFunc) {
+	if c.openai.OverallTimeout <= 0 || client == c.streamClient {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.openai.OverallTimeout)
+}
+
+// cancelOnClose is a struct to release request context after response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close is a function to close response body and release request context
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+func durationOrDefault(value, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
+
+func intOrDefault(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+
+	return value
+}
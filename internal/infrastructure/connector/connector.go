@@ -3,13 +3,16 @@ package connector
 import (
 	"context"
 	"io"
+	"net/http"
 )
 
 //go:generate rm -f ./connector_mock.go
-//go:generate mockgen -destination connector_mock.go -package connector -mock_names Connector=GoMockConnector -source connector.go
+//go:generate mockgen -destination connector_mock.go -package connector -mock_names Connector=GoMockConnector,EventStream=GoMockEventStream -source connector.go
 
 type connector struct {
-	openai *OpenAI
+	openai       *OpenAI
+	client       *http.Client
+	streamClient *http.Client
 }
 
 // RequestOption is a struct to set HTTP Request Parameter
@@ -18,23 +21,45 @@ type RequestOption struct {
 	URL          string
 	Body         io.Reader
 	CustomHeader map[string]string
+	// Multipart is streamed as multipart/form-data body when it is set, Body is ignored
+	// The request is sent only once because the streamed body cannot be replayed
+	Multipart []MultipartField
 }
 
 // NewConnector is a function to create new HTTP connector
 func NewConnector(openai *OpenAI) Connector {
+	client := newHTTPClient(openai)
+
 	return &connector{
-		openai: openai,
+		openai:       openai,
+		client:       client,
+		streamClient: newStreamClient(client),
 	}
 }
 
 // Connector is an interface to send HTTP Request
 type Connector interface {
 	Send(ctx context.Context, requestOption *RequestOption, response any) error
+	Stream(ctx context.Context, requestOption *RequestOption) (EventStream, error)
+	SendRaw(ctx context.Context, requestOption *RequestOption) (*RawResponse, error)
+}
+
+// EventStream is an interface to read Server-Sent Events from OpenAI API
+// Events are read from the connection only when Recv is called, so a slow consumer slows down the producer
+type EventStream interface {
+	// Recv returns the next event, io.EOF is returned after the done event or when the stream ends
+	Recv() (*Event, error)
+	// Close releases the underlying connection, it is safe to call more than once
+	Close() error
 }
 
 const (
-	HeaderAuthorization = "Authorization"
-	BearerAuthType      = "Bearer"
-	HeaderKeyOpenAIBeta = "OpenAI-Beta"
-	AssistantV1         = "assistants=v1"
+	HeaderAuthorization      = "Authorization"
+	BearerAuthType           = "Bearer"
+	HeaderKeyOpenAIBeta      = "OpenAI-Beta"
+	AssistantV1              = "assistants=v1"
+	HeaderAccept             = "Accept"
+	HeaderContentType        = "Content-Type"
+	HeaderContentDisposition = "Content-Disposition"
+	EventStreamMIME          = "text/event-stream"
 )
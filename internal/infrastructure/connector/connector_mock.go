@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -destination connector_mock.go -package connector -mock_names Connector=GoMockConnector -source connector.go
+//	mockgen -destination connector_mock.go -package connector -mock_names Connector=GoMockConnector,EventStream=GoMockEventStream -source connector.go
 //
 
 // Package connector is a generated GoMock package.
@@ -52,3 +52,85 @@ func (mr *GoMockConnectorMockRecorder) Send(ctx, requestOption, response any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*GoMockConnector)(nil).Send), ctx, requestOption, response)
 }
+
+// SendRaw mocks base method.
+func (m *GoMockConnector) SendRaw(ctx context.Context, requestOption *RequestOption) (*RawResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRaw", ctx, requestOption)
+	ret0, _ := ret[0].(*RawResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendRaw indicates an expected call of SendRaw.
+func (mr *GoMockConnectorMockRecorder) SendRaw(ctx, requestOption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRaw", reflect.TypeOf((*GoMockConnector)(nil).SendRaw), ctx, requestOption)
+}
+
+// Stream mocks base method.
+func (m *GoMockConnector) Stream(ctx context.Context, requestOption *RequestOption) (EventStream, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stream", ctx, requestOption)
+	ret0, _ := ret[0].(EventStream)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Stream indicates an expected call of Stream.
+func (mr *GoMockConnectorMockRecorder) Stream(ctx, requestOption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*GoMockConnector)(nil).Stream), ctx, requestOption)
+}
+
+// GoMockEventStream is a mock of EventStream interface.
+type GoMockEventStream struct {
+	ctrl     *gomock.Controller
+	recorder *GoMockEventStreamMockRecorder
+}
+
+// GoMockEventStreamMockRecorder is the mock recorder for GoMockEventStream.
+type GoMockEventStreamMockRecorder struct {
+	mock *GoMockEventStream
+}
+
+// NewGoMockEventStream creates a new mock instance.
+func NewGoMockEventStream(ctrl *gomock.Controller) *GoMockEventStream {
+	mock := &GoMockEventStream{ctrl: ctrl}
+	mock.recorder = &GoMockEventStreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *GoMockEventStream) EXPECT() *GoMockEventStreamMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *GoMockEventStream) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *GoMockEventStreamMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*GoMockEventStream)(nil).Close))
+}
+
+// Recv mocks base method.
+func (m *GoMockEventStream) Recv() (*Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Recv")
+	ret0, _ := ret[0].(*Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Recv indicates an expected call of Recv.
+func (mr *GoMockEventStreamMockRecorder) Recv() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recv", reflect.TypeOf((*GoMockEventStream)(nil).Recv))
+}
@@ -0,0 +1,161 @@
+package connector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	HeaderRequestID = "X-Request-Id"
+
+	// maxErrorBodySize is the maximum size of error body that will be read from OpenAI API
+	maxErrorBodySize = 1 << 16
+)
+
+// APIError is an error returned by OpenAI API on non-2xx response
+type APIError struct {
+	HTTPStatus int    `json:"-"`
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	Param      string `json:"param"`
+	Message    string `json:"message"`
+	RequestID  string `json:"-"`
+}
+
+// Error is a function to implement error interface
+func (e *APIError) Error() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "openai: status %d", e.HTTPStatus)
+
+	if e.Type != "" {
+		fmt.Fprintf(&b, " type=%s", e.Type)
+	}
+
+	if e.Code != "" {
+		fmt.Fprintf(&b, " code=%s", e.Code)
+	}
+
+	if e.Param != "" {
+		fmt.Fprintf(&b, " param=%s", e.Param)
+	}
+
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request_id=%s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// apiErrorResponse is a struct of error body from OpenAI API
+type apiErrorResponse struct {
+	Error *struct {
+		Type    string `json:"type"`
+		Code    any    `json:"code"`
+		Param   any    `json:"param"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// newAPIError is a function to build APIError from non-2xx HTTP Response
+// If the body is not an OpenAI error object, the raw body will be used as message
+func newAPIError(response *http.Response) *APIError {
+	apiErr := &APIError{
+		HTTPStatus: response.StatusCode,
+		RequestID:  response.Header.Get(HeaderRequestID),
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	if err != nil || len(body) == 0 {
+		apiErr.Message = http.StatusText(response.StatusCode)
+
+		return apiErr
+	}
+
+	var errResponse apiErrorResponse
+	if err := json.Unmarshal(body, &errResponse); err != nil || errResponse.Error == nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+
+		return apiErr
+	}
+
+	apiErr.Type = errResponse.Error.Type
+	apiErr.Code = stringify(errResponse.Error.Code)
+	apiErr.Param = stringify(errResponse.Error.Param)
+	apiErr.Message = errResponse.Error.Message
+
+	return apiErr
+}
+
+// stringify is a function to convert loosely typed JSON value into string
+func stringify(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// AsAPIError is a function to get APIError from error chain
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+
+	return nil, false
+}
+
+// hasStatus is a function to check if error is APIError with one of the given HTTP status
+func hasStatus(err error, statuses ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+
+	for _, status := range statuses {
+		if apiErr.HTTPStatus == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsRateLimited is a function to check if error is caused by OpenAI rate limit or quota
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsAuth is a function to check if error is caused by invalid or unauthorized API key
+func IsAuth(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsNotFound is a function to check if error is caused by unknown OpenAI resource
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsBadRequest is a function to check if error is caused by invalid request parameter
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
+
+// IsServerError is a function to check if error is caused by OpenAI server failure
+func IsServerError(err error) bool {
+	apiErr, ok := AsAPIError(err)
+
+	return ok && apiErr.HTTPStatus >= http.StatusInternalServerError
+}
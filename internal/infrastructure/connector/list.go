@@ -0,0 +1,137 @@
+package connector
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+)
+
+const listQueryAfter = "after"
+
+var ErrList = errors.New("failed to get list")
+
+// OpenAIList is a struct of a page of list from OpenAI API, e.g. files, assistants, threads, messages or runs
+type OpenAIList[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id"`
+	LastID  string `json:"last_id"`
+	HasMore bool   `json:"has_more"`
+}
+
+// ListIterator is a struct to iterate items of list from OpenAI API page by page using the after cursor
+// The next page is requested only when the items of the current page are used up
+type ListIterator[T any] struct {
+	connector     Connector
+	requestOption RequestOption
+	query         url.Values
+	page          *OpenAIList[T]
+	index         int
+	lastID        string
+	err           error
+}
+
+// NewListIterator is a function to create iterator of list, query is sent with every page
+// The URL of request option must not contain query, the after cursor of query is used for the first page only
+func NewListIterator[T any](connector Connector, requestOption *RequestOption, query url.Values) *ListIterator[T] {
+	return &ListIterator[T]{
+		connector:     connector,
+		requestOption: *requestOption,
+		query:         maps.Clone(query),
+	}
+}
+
+// ListAll is a function to get all items of list from OpenAI API page by page
+func ListAll[T any](ctx context.Context, connector Connector, requestOption *RequestOption, query url.Values) ([]T, error) {
+	iterator := NewListIterator[T](connector, requestOption, query)
+
+	var items []T
+	for iterator.Next(ctx) {
+		items = append(items, iterator.Item())
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Next is a function to move to the next item, the next page is requested when the current page is used up
+// It returns false when there is no item left or the request fails, Err tells which one
+func (it *ListIterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+
+	for it.page == nil || it.index >= len(it.page.Data) {
+		if it.page != nil && !it.hasNextPage() {
+			return false
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	return true
+}
+
+// Item is a function to get the current item, it must be called only after Next returns true
+func (it *ListIterator[T]) Item() T {
+	return it.page.Data[it.index]
+}
+
+// LastID is a function to get the cursor of the last page that is read
+func (it *ListIterator[T]) LastID() string {
+	return it.lastID
+}
+
+// Err is a function to get the error that stops the iteration
+func (it *ListIterator[T]) Err() error {
+	return it.err
+}
+
+// hasNextPage is a function to check whether the next page can be requested
+// A page without new cursor is the last one, so a list that repeats its cursor does not loop forever
+func (it *ListIterator[T]) hasNextPage() bool {
+	return it.page.HasMore && it.page.LastID != "" && it.page.LastID != it.query.Get(listQueryAfter)
+}
+
+// fetch is a function to request the next page of list
+func (it *ListIterator[T]) fetch(ctx context.Context) error {
+	if it.page != nil {
+		if it.query == nil {
+			it.query = url.Values{}
+		}
+
+		it.query.Set(listQueryAfter, it.page.LastID)
+	}
+
+	requestOption := it.requestOption
+	if len(it.query) > 0 {
+		requestOption.URL += "?" + it.query.Encode()
+	}
+
+	var result *OpenAIList[T]
+	if err := it.connector.Send(ctx, &requestOption, &result); err != nil {
+		return err
+	}
+
+	if result == nil {
+		return ErrList
+	}
+
+	it.page = result
+	it.index = 0
+
+	if result.LastID != "" {
+		it.lastID = result.LastID
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package connector_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestListIterator(t *testing.T) {
+	type test struct {
+		fields     fields
+		query      url.Values
+		want       []string
+		wantLastID string
+		wantErr    error
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"Given several pages, When iterating, Return every item with the query of every page": func(t *testing.T) test {
+			pages := map[string]string{
+				"":        `{"object":"list","data":[{"id":"msg-1"},{"id":"msg-2"}],"last_id":"msg-2","has_more":true}`,
+				"msg-2":   `{"object":"list","data":[],"last_id":"msg-2b","has_more":true}`,
+				"msg-2b":  `{"object":"list","data":[{"id":"msg-3"}],"last_id":"msg-3","has_more":false}`,
+				"unknown": `{"object":"list","data":[{"id":"unexpected"}]}`,
+			}
+
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/threads/thread-1/messages", r.URL.Path)
+				assert.Equal(t, "asc", r.URL.Query().Get("order"))
+
+				_, _ = w.Write([]byte(pages[r.URL.Query().Get("after")]))
+			})
+
+			return test{
+				fields:     fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				query:      url.Values{"order": []string{"asc"}},
+				want:       []string{"msg-1", "msg-2", "msg-3"},
+				wantLastID: "msg-3",
+			}
+		},
+		"Given page that repeats its cursor, When iterating, Return items without looping forever": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"msg-1"}],"last_id":"msg-1","has_more":true}`))
+			})
+
+			return test{
+				fields:     fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				want:       []string{"msg-1", "msg-1"},
+				wantLastID: "msg-1",
+			}
+		},
+		"Given page without body, When iterating, Return list error": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`null`))
+			})
+
+			return test{
+				fields:  fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				wantErr: connector.ErrList,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			iterator := connector.NewListIterator[repository.Message](sut(tt.fields), &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/threads/thread-1/messages",
+			}, tt.query)
+
+			var got []string
+			for iterator.Next(context.Background()) {
+				got = append(got, iterator.Item().ID)
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, iterator.Err(), tt.wantErr)
+			} else {
+				assert.NoError(t, iterator.Err())
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantLastID, iterator.LastID())
+		})
+	}
+}
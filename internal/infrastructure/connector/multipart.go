@@ -0,0 +1,122 @@
+package connector
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+const defaultMultipartContentType = "application/octet-stream"
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// MultipartField is a struct of field of multipart/form-data body
+// Field with Reader is a file that is streamed, otherwise Value is sent as text field
+type MultipartField struct {
+	Name        string
+	Value       string
+	Filename    string
+	ContentType string
+	Reader      io.Reader
+	// Size is the length of Reader, zero means it is unknown and the body is sent chunked
+	Size int64
+}
+
+// newMultipartBody is a function to stream fields as multipart/form-data body through a pipe
+// The fields are written only as fast as the body is read, so the file is never buffered in memory
+// It returns the body, its content type with the boundary and its length, or zero when it is unknown
+func newMultipartBody(fields []MultipartField) (io.ReadCloser, string, int64) {
+	reader, writer := io.Pipe()
+	multipartWriter := multipart.NewWriter(writer)
+
+	contentLength := multipartLength(multipartWriter.Boundary(), fields)
+
+	go func() {
+		err := writeMultipart(multipartWriter, fields, true)
+		if err == nil {
+			err = multipartWriter.Close()
+		}
+
+		// Reader gets io.EOF when err is nil, otherwise the request fails with err
+		writer.CloseWithError(err)
+	}()
+
+	return reader, multipartWriter.FormDataContentType(), contentLength
+}
+
+// multipartLength is a function to count the length of multipart body without reading the files
+// It returns zero when the size of any file is unknown
+func multipartLength(boundary string, fields []MultipartField) int64 {
+	var counter countWriter
+
+	multipartWriter := multipart.NewWriter(&counter)
+	if err := multipartWriter.SetBoundary(boundary); err != nil {
+		return 0
+	}
+
+	for _, field := range fields {
+		if field.Reader != nil && field.Size <= 0 {
+			return 0
+		}
+
+		counter += countWriter(field.Size)
+	}
+
+	if err := writeMultipart(multipartWriter, fields, false); err != nil {
+		return 0
+	}
+
+	if err := multipartWriter.Close(); err != nil {
+		return 0
+	}
+
+	return int64(counter)
+}
+
+// writeMultipart is a function to write fields into multipart writer, content of file is copied only when copyFiles is true
+func writeMultipart(multipartWriter *multipart.Writer, fields []MultipartField, copyFiles bool) error {
+	for _, field := range fields {
+		if field.Reader == nil {
+			if err := multipartWriter.WriteField(field.Name, field.Value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		contentType := field.ContentType
+		if contentType == "" {
+			contentType = defaultMultipartContentType
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(field.Name), quoteEscaper.Replace(field.Filename)))
+		header.Set(HeaderContentType, contentType)
+
+		part, err := multipartWriter.CreatePart(header)
+		if err != nil {
+			return err
+		}
+
+		if !copyFiles {
+			continue
+		}
+
+		if _, err := io.Copy(part, field.Reader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countWriter is a writer that only counts the written bytes
+type countWriter int64
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	*w += countWriter(len(p))
+	return len(p), nil
+}
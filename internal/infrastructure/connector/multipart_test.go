@@ -0,0 +1,148 @@
+package connector_test
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+func TestConnector_SendMultipart(t *testing.T) {
+	type test struct {
+		fields        fields
+		multipart     []connector.MultipartField
+		want          *repository.File
+		wantErr       error
+		wantServerErr bool
+	}
+
+	errRead := errors.New("read failed")
+
+	// checkUpload is a handler of fake OpenAI API that checks the uploaded form
+	checkUpload := func(t *testing.T, wantLength int64) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, wantLength, r.ContentLength)
+			assert.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data; boundary="))
+
+			assert.NoError(t, r.ParseMultipartForm(1024))
+			assert.Equal(t, "assistants", r.FormValue("purpose"))
+
+			file, header, err := r.FormFile("file")
+			assert.NoError(t, err)
+			assert.Equal(t, "notes.txt", header.Filename)
+			assert.Equal(t, "text/plain", header.Header.Get("Content-Type"))
+
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "hello world", string(content))
+
+			_, _ = w.Write([]byte(`{"id":"file-1","object":"file","purpose":"assistants","filename":"notes.txt","bytes":11}`))
+		}
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"Given file with known size, When request is sent, Return uploaded file with exact content length": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Positive(t, r.ContentLength)
+				checkUpload(t, r.ContentLength)(w, r)
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				multipart: []connector.MultipartField{
+					{Name: "purpose", Value: "assistants"},
+					{Name: "file", Filename: "notes.txt", ContentType: "text/plain", Reader: strings.NewReader("hello world"), Size: 11},
+				},
+				want: &repository.File{ID: "file-1", Object: "file", Purpose: "assistants", Filename: "notes.txt", Bytes: 11},
+			}
+		},
+		"Given file with unknown size, When request is sent, Return uploaded file with chunked body": func(t *testing.T) test {
+			server := newServer(t, checkUpload(t, -1))
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				multipart: []connector.MultipartField{
+					{Name: "purpose", Value: "assistants"},
+					{Name: "file", Filename: "notes.txt", ContentType: "text/plain", Reader: io.MultiReader(strings.NewReader("hello "), strings.NewReader("world"))},
+				},
+				want: &repository.File{ID: "file-1", Object: "file", Purpose: "assistants", Filename: "notes.txt", Bytes: 11},
+			}
+		},
+		"Given file that fails to be read, When request is sent, Return the read error": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.ReadAll(r.Body)
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				multipart: []connector.MultipartField{
+					{Name: "file", Filename: "notes.txt", Reader: failingReader{err: errRead}},
+				},
+				wantErr: errRead,
+			}
+		},
+		"Given server error, When request is sent, Return error without retrying the streamed body": func(t *testing.T) test {
+			var calls atomic.Int32
+
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				_, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			t.Cleanup(func() {
+				assert.Equal(t, int32(1), calls.Load())
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{
+					BaseURL: server.URL,
+					Retry:   &connector.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+				}},
+				multipart: []connector.MultipartField{
+					{Name: "file", Filename: "notes.txt", Reader: strings.NewReader("hello world"), Size: 11},
+				},
+				wantServerErr: true,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			sut := sut(tt.fields)
+
+			var result *repository.File
+			err := sut.Send(context.Background(), &connector.RequestOption{
+				Method:    http.MethodPost,
+				URL:       "/files",
+				Multipart: tt.multipart,
+			}, &result)
+			switch {
+			case tt.wantServerErr:
+				assert.True(t, connector.IsServerError(err))
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			default:
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, result)
+		})
+	}
+}
@@ -2,6 +2,8 @@ package connector
 
 import (
 	"errors"
+	"net/http"
+	"time"
 
 	"github.com/yonisaka/assistant/internal/entities/repository"
 )
@@ -10,22 +12,32 @@ import (
 type OpenAI struct {
 	BaseURL string
 	Header  map[string]string
+	Retry   *RetryPolicy
+	// Client is used as is when it is set, Transport, Timeout and TransportConfig will be ignored
+	Client *http.Client
+	// Transport is used instead of the transport built from TransportConfig when it is set
+	Transport http.RoundTripper
+	// Timeout is the time limit of each attempt, including reading the response body
+	Timeout time.Duration
+	// OverallTimeout is the time limit of the whole request, including all retries
+	OverallTimeout  time.Duration
+	TransportConfig *TransportConfig
 }
 
 // OpenAIFile is a struct to get list file from OpenAI API
-type OpenAIFile struct {
-	Object  string            `json:"object"`
-	HasMore bool              `json:"has_more"`
-	Data    []repository.File `json:"data"`
-}
+type OpenAIFile = OpenAIList[repository.File]
 
 // OpenAIMessage is a struct to get response prompt
-type OpenAIMessage struct {
-	Object  string               `json:"object"`
-	Data    []repository.Message `json:"data"`
-	FirstID string               `json:"first_id"`
-	LastID  string               `json:"last_id"`
-	HasMore bool                 `json:"has_more"`
+type OpenAIMessage = OpenAIList[repository.Message]
+
+// OpenAIAssistant is a struct to get list assistant
+type OpenAIAssistant = OpenAIList[repository.Assistant]
+
+// OpenAIDeleted is a struct of response after an object is deleted
+type OpenAIDeleted struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
 }
 
 // OpenAIThread is a struct to get thread
@@ -37,39 +49,117 @@ type OpenAIThread struct {
 	HasMore bool                `json:"has_more"`
 }
 
+// OpenAIRun is a struct to get run, see run.go for the run lifecycle
 type OpenAIRun struct {
-	ID           string       `json:"id"`
-	Object       string       `json:"object"`
-	CreatedAt    int64        `json:"created_at"`
-	AssistantID  string       `json:"assistant_id"`
-	ThreadID     string       `json:"thread_id"`
-	Status       string       `json:"status"`
-	StartedAt    interface{}  `json:"started_at"`
-	ExpiresAt    int64        `json:"expires_at"`
-	CancelledAt  interface{}  `json:"cancelled_at"`
-	FailedAt     interface{}  `json:"failed_at"`
-	CompletedAt  interface{}  `json:"completed_at"`
-	LastError    interface{}  `json:"last_error"`
-	Model        string       `json:"model"`
-	Instructions string       `json:"instructions"`
-	Tools        []OpenAITool `json:"tools"`
-	FileIDS      []string     `json:"file_ids"`
-	Metadata     interface{}  `json:"metadata"`
+	ID                string                     `json:"id"`
+	Object            string                     `json:"object"`
+	CreatedAt         int64                      `json:"created_at"`
+	AssistantID       string                     `json:"assistant_id"`
+	ThreadID          string                     `json:"thread_id"`
+	Status            string                     `json:"status"`
+	RequiredAction    *RequiredAction            `json:"required_action"`
+	LastError         *RunLastError              `json:"last_error"`
+	IncompleteDetails *IncompleteDetails         `json:"incomplete_details"`
+	StartedAt         *int64                     `json:"started_at"`
+	ExpiresAt         int64                      `json:"expires_at"`
+	CancelledAt       *int64                     `json:"cancelled_at"`
+	FailedAt          *int64                     `json:"failed_at"`
+	CompletedAt       *int64                     `json:"completed_at"`
+	Model             string                     `json:"model"`
+	Instructions      string                     `json:"instructions"`
+	Tools             []repository.AssistantTool `json:"tools"`
+	FileIDS           []string                   `json:"file_ids"`
+	Metadata          interface{}                `json:"metadata"`
+	Usage             *RunUsage                  `json:"usage"`
+}
+
+// RunLastError is a struct of the reason the run failed
+type RunLastError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IncompleteDetails is a struct of the reason the run ended without completing
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// RunUsage is a struct of token usage of the run, it is only set when the run is terminal
+type RunUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// RequiredAction is a struct of action required to continue the run
+type RequiredAction struct {
+	Type              string            `json:"type"`
+	SubmitToolOutputs SubmitToolOutputs `json:"submit_tool_outputs"`
+}
+
+type SubmitToolOutputs struct {
+	ToolCalls []ToolCall `json:"tool_calls"`
 }
 
-type OpenAITool struct {
-	Type string `json:"type"`
+// ToolCall is a struct of function call requested by the assistant
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// OpenAIMessageDelta is a struct to get streamed message delta
+type OpenAIMessageDelta struct {
+	ID     string       `json:"id"`
+	Object string       `json:"object"`
+	Delta  MessageDelta `json:"delta"`
+}
+
+type MessageDelta struct {
+	Role    string         `json:"role"`
+	Content []ContentDelta `json:"content"`
+}
+
+type ContentDelta struct {
+	Index int `json:"index"`
+	repository.ContentMessage
+}
+
+// Text is a function to get text content of message delta, other content types are skipped
+func (d *OpenAIMessageDelta) Text() string {
+	var text string
+
+	for _, content := range d.Delta.Content {
+		if content.Text != nil {
+			text += content.Text.Value
+		}
+	}
+
+	return text
 }
 
 var (
 	ErrCreateThread    = errors.New("failed to create new thread")
+	ErrModifyThread    = errors.New("failed to modify thread")
 	ErrCreateMessage   = errors.New("failed to create new message")
 	ErrRunThread       = errors.New("failed to run thread")
 	ErrRunStatusThread = errors.New("failed to run status thread")
 	ErrGetPrompt       = errors.New("failed to get prompt response")
+	ErrSubmitToolCall  = errors.New("failed to submit tool outputs")
+	ErrCancelRun       = errors.New("failed to cancel run")
+	ErrAssistant       = errors.New("failed to get assistant")
+	ErrFile            = errors.New("failed to get file")
+	ErrDelete          = errors.New("failed to delete object")
 )
 
 const (
-	OpenAIStatusQueued     = "queued"
-	OpenAIStatusInProgress = "in_progress"
+	OpenAIToolTypeFunction                = "function"
+	OpenAIToolTypeCodeInterpreter         = "code_interpreter"
+	OpenAIToolTypeRetrieval               = "retrieval"
+	OpenAIRequiredActionSubmitToolOutputs = "submit_tool_outputs"
 )
@@ -1,13 +1,47 @@
 package connector
 
+import "github.com/yonisaka/assistant/internal/entities/repository"
+
 type (
 	RequestMessage struct {
-		Role    string `json:"role"`
-		Content string `json:"content"`
+		Role    string   `json:"role"`
+		Content string   `json:"content"`
+		FileIDs []string `json:"file_ids,omitempty"`
+	}
+
+	// RequestThread is used to modify thread, metadata replaces the metadata of the thread
+	RequestThread struct {
+		Metadata map[string]string `json:"metadata"`
 	}
 
 	RequestRun struct {
-		AssistantID  string `json:"assistant_id"`
-		Instructions string `json:"instructions"`
+		AssistantID            string                     `json:"assistant_id"`
+		Model                  string                     `json:"model,omitempty"`
+		Instructions           string                     `json:"instructions,omitempty"`
+		AdditionalInstructions string                     `json:"additional_instructions,omitempty"`
+		Tools                  []repository.AssistantTool `json:"tools,omitempty"`
+		Metadata               map[string]string          `json:"metadata,omitempty"`
+		Stream                 bool                       `json:"stream,omitempty"`
+	}
+
+	// RequestAssistant is used to create and modify assistant, nil field is not changed on modify
+	RequestAssistant struct {
+		Model        string                      `json:"model,omitempty"`
+		Name         *string                     `json:"name,omitempty"`
+		Description  *string                     `json:"description,omitempty"`
+		Instructions *string                     `json:"instructions,omitempty"`
+		Tools        *[]repository.AssistantTool `json:"tools,omitempty"`
+		FileIDs      *[]string                   `json:"file_ids,omitempty"`
+		Metadata     map[string]string           `json:"metadata,omitempty"`
+	}
+
+	RequestToolOutputs struct {
+		ToolOutputs []ToolOutput `json:"tool_outputs"`
+		Stream      bool         `json:"stream,omitempty"`
+	}
+
+	ToolOutput struct {
+		ToolCallID string `json:"tool_call_id"`
+		Output     string `json:"output"`
 	}
 )
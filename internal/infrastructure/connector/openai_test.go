@@ -1,14 +1,20 @@
 package connector_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/gofiber/fiber/v2/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/entities/repository"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type fields struct {
@@ -28,12 +34,21 @@ func TestMain(m *testing.M) {
 		os.Exit(code)
 	}()
 
-	_ = os.Setenv("OPENAI_API_URL", "test")
 	_ = os.Setenv("OPENAI_API_KEY", "test")
 
 	code = m.Run()
 }
 
+// newServer is a function to create fake OpenAI API server
+func newServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
 func TestConnector_Send(t *testing.T) {
 	type args struct {
 		ctx           context.Context
@@ -44,13 +59,22 @@ func TestConnector_Send(t *testing.T) {
 	type test struct {
 		fields  fields
 		args    args
+		want    connector.OpenAIFile
 		wantErr error
+		errFn   func(err error) bool
 	}
 
 	tests := map[string]func(t *testing.T) test{
 		"success": func(t *testing.T) test {
 			ctx := context.Background()
 
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/files", r.URL.Path)
+				assert.Equal(t, fmt.Sprintf("Bearer %s", os.Getenv("OPENAI_API_KEY")), r.Header.Get("Authorization"))
+
+				_, _ = w.Write([]byte(`{"object":"list","has_more":false,"data":[{"id":"file-1","object":"file"}]}`))
+			})
+
 			args := args{
 				ctx: ctx,
 				requestOption: &connector.RequestOption{
@@ -62,16 +86,84 @@ func TestConnector_Send(t *testing.T) {
 			return test{
 				fields: fields{
 					openai: &connector.OpenAI{
-						BaseURL: os.Getenv("OPENAI_API_URL"),
+						BaseURL: server.URL,
 						Header: map[string]string{
 							"Authorization": fmt.Sprintf("Bearer %s", os.Getenv("OPENAI_API_KEY")),
 						},
 					},
 				},
-				args:    args,
+				args: args,
+				want: connector.OpenAIFile{
+					Object: "list",
+					Data:   []repository.File{{ID: "file-1", Object: "file"}},
+				},
 				wantErr: nil,
 			}
 		},
+		"rate limited": func(t *testing.T) test {
+			ctx := context.Background()
+
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(connector.HeaderRequestID, "req-1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				_, _ = w.Write([]byte(`{"error":{"message":"Rate limit reached","type":"requests","param":null,"code":"rate_limit_exceeded"}}`))
+			})
+
+			args := args{
+				ctx: ctx,
+				requestOption: &connector.RequestOption{
+					Method: http.MethodGet,
+					URL:    "/files",
+				},
+			}
+
+			return test{
+				fields: fields{
+					openai: &connector.OpenAI{
+						BaseURL: server.URL,
+					},
+				},
+				args: args,
+				wantErr: &connector.APIError{
+					HTTPStatus: http.StatusTooManyRequests,
+					Type:       "requests",
+					Code:       "rate_limit_exceeded",
+					Message:    "Rate limit reached",
+					RequestID:  "req-1",
+				},
+				errFn: connector.IsRateLimited,
+			}
+		},
+		"unauthorized with non json body": func(t *testing.T) test {
+			ctx := context.Background()
+
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte("invalid api key\n"))
+			})
+
+			args := args{
+				ctx: ctx,
+				requestOption: &connector.RequestOption{
+					Method: http.MethodGet,
+					URL:    "/files",
+				},
+			}
+
+			return test{
+				fields: fields{
+					openai: &connector.OpenAI{
+						BaseURL: server.URL,
+					},
+				},
+				args: args,
+				wantErr: &connector.APIError{
+					HTTPStatus: http.StatusUnauthorized,
+					Message:    "invalid api key",
+				},
+				errFn: connector.IsAuth,
+			}
+		},
 	}
 
 	for name, testFn := range tests {
@@ -83,12 +175,197 @@ func TestConnector_Send(t *testing.T) {
 			var result connector.OpenAIFile
 			err := sut.Send(tt.args.ctx, tt.args.requestOption, &result)
 			if tt.wantErr != nil {
-				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, tt.wantErr, err)
+				assert.True(t, tt.errFn(err))
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.want, result)
 			}
 
 			log.Info(result)
 		})
 	}
 }
+
+func TestConnector_SendRetry(t *testing.T) {
+	type test struct {
+		fields       fields
+		method       string
+		body         io.Reader
+		wantAttempts int32
+		wantErr      bool
+	}
+
+	retry := &connector.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+
+	tests := map[string]func(t *testing.T, attempts *int32) test{
+		"retry rate limited request with Retry-After and replay body": func(t *testing.T, attempts *int32) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, `{"role":"user"}`, string(body))
+
+				if atomic.AddInt32(attempts, 1) < 3 {
+					w.Header().Set(connector.HeaderRetryAfter, "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+
+					return
+				}
+
+				_, _ = w.Write([]byte(`{}`))
+			})
+
+			return test{
+				fields:       fields{openai: &connector.OpenAI{BaseURL: server.URL, Retry: retry}},
+				method:       http.MethodPost,
+				body:         bytes.NewBufferString(`{"role":"user"}`),
+				wantAttempts: 3,
+			}
+		},
+		"retry server error on GET until max attempts": func(t *testing.T, attempts *int32) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(attempts, 1)
+				w.WriteHeader(http.StatusBadGateway)
+			})
+
+			return test{
+				fields:       fields{openai: &connector.OpenAI{BaseURL: server.URL, Retry: retry}},
+				method:       http.MethodGet,
+				wantAttempts: 3,
+				wantErr:      true,
+			}
+		},
+		"do not retry server error on POST": func(t *testing.T, attempts *int32) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(attempts, 1)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			return test{
+				fields:       fields{openai: &connector.OpenAI{BaseURL: server.URL, Retry: retry}},
+				method:       http.MethodPost,
+				body:         bytes.NewBufferString(`{}`),
+				wantAttempts: 1,
+				wantErr:      true,
+			}
+		},
+		"do not retry non replayable body": func(t *testing.T, attempts *int32) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(attempts, 1)
+				w.WriteHeader(http.StatusTooManyRequests)
+			})
+
+			return test{
+				fields:       fields{openai: &connector.OpenAI{BaseURL: server.URL, Retry: retry}},
+				method:       http.MethodPost,
+				body:         io.MultiReader(bytes.NewBufferString(`{}`)),
+				wantAttempts: 1,
+				wantErr:      true,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+
+			tt := testFn(t, &attempts)
+
+			sut := sut(tt.fields)
+
+			var result map[string]any
+			err := sut.Send(context.Background(), &connector.RequestOption{
+				Method: tt.method,
+				URL:    "/threads",
+				Body:   tt.body,
+			}, &result)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantAttempts, atomic.LoadInt32(&attempts))
+		})
+	}
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestConnector_SendHTTPClient(t *testing.T) {
+	type test struct {
+		fields  fields
+		wantErr bool
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"use injected transport": func(t *testing.T) test {
+			transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				assert.Equal(t, "http://openai.test/files", r.URL.String())
+
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(bytes.NewBufferString(`{"object":"list"}`)),
+				}, nil
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: "http://openai.test", Transport: transport}},
+			}
+		},
+		"stop stuck request after timeout": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			})
+
+			return test{
+				fields:  fields{openai: &connector.OpenAI{BaseURL: server.URL, Timeout: 20 * time.Millisecond}},
+				wantErr: true,
+			}
+		},
+		"stop retried request after overall timeout": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{
+					BaseURL:        server.URL,
+					OverallTimeout: 20 * time.Millisecond,
+					Retry:          &connector.RetryPolicy{MaxAttempts: 100, BaseDelay: 5 * time.Millisecond},
+				}},
+				wantErr: true,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			sut := sut(tt.fields)
+
+			var result connector.OpenAIFile
+			err := sut.Send(context.Background(), &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files",
+			}, &result)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
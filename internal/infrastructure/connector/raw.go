@@ -0,0 +1,34 @@
+package connector
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// RawResponse is a struct of HTTP Response that is not decoded
+// ContentLength is -1 when it is unknown, the caller must close the Body
+type RawResponse struct {
+	Body          io.ReadCloser
+	Header        http.Header
+	ContentLength int64
+}
+
+// SendRaw is a function to send HTTP Request and return the response body as it is
+// It uses the client without timeout, so reading a large body is bounded only by the context
+func (c *connector) SendRaw(ctx context.Context, requestOption *RequestOption) (*RawResponse, error) {
+	response, err := c.do(ctx, requestOption, c.streamClient)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Raw Response Opened:", "url", requestOption.URL, "content_length", response.ContentLength)
+
+	return &RawResponse{
+		Body:          response.Body,
+		Header:        response.Header,
+		ContentLength: response.ContentLength,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package connector_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestConnector_SendRaw(t *testing.T) {
+	type test struct {
+		fields          fields
+		wantBody        string
+		wantContentType string
+		wantLength      int64
+		wantNotFound    bool
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"Given file content, When request is sent, Return body and headers as they are": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/files/file-1/content", r.URL.Path)
+
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("hello world"))
+			})
+
+			return test{
+				fields:          fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				wantBody:        "hello world",
+				wantContentType: "text/plain",
+				wantLength:      11,
+			}
+		},
+		"Given chunked file content, When request is sent, Return body with unknown length": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("hello "))
+				w.(http.Flusher).Flush()
+				_, _ = w.Write([]byte("world"))
+			})
+
+			return test{
+				fields:          fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				wantBody:        "hello world",
+				wantContentType: "text/plain; charset=utf-8",
+				wantLength:      -1,
+			}
+		},
+		"Given missing file, When request is sent, Return not found error": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"message":"No such File object: file-1","type":"invalid_request_error"}}`))
+			})
+
+			return test{
+				fields:       fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				wantNotFound: true,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			sut := sut(tt.fields)
+
+			got, err := sut.SendRaw(context.Background(), &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files/file-1/content",
+			})
+			if tt.wantNotFound {
+				assert.True(t, connector.IsNotFound(err))
+				assert.Nil(t, got)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			defer got.Body.Close()
+
+			body, err := io.ReadAll(got.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBody, string(body))
+			assert.Equal(t, tt.wantContentType, got.Header.Get("Content-Type"))
+			assert.Equal(t, tt.wantLength, got.ContentLength)
+		})
+	}
+}
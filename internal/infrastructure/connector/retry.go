@@ -0,0 +1,223 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderRetryAfter                 = "Retry-After"
+	HeaderRetryAfterMs               = "Retry-After-Ms"
+	HeaderIdempotencyKey             = "Idempotency-Key"
+	HeaderRateLimitRemainingRequests = "X-Ratelimit-Remaining-Requests"
+	HeaderRateLimitRemainingTokens   = "X-Ratelimit-Remaining-Tokens"
+	HeaderRateLimitResetRequests     = "X-Ratelimit-Reset-Requests"
+	HeaderRateLimitResetTokens       = "X-Ratelimit-Reset-Tokens"
+
+	// rateLimitExhausted is the value of x-ratelimit-remaining-* header when the limit is reached
+	rateLimitExhausted = "0"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 20 * time.Second
+	DefaultRetryJitter      = 0.2
+)
+
+// RetryPolicy is a struct to set retry behaviour of HTTP Request
+// A nil RetryPolicy or MaxAttempts lower than 2 means the request is sent only once
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, it is doubled on every next retry
+	BaseDelay time.Duration
+	// MaxDelay is the upper bound of delay between attempts, including delay from response header
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of the delay that is randomized
+	Jitter float64
+}
+
+// DefaultRetryPolicy is a function to get recommended retry policy for OpenAI API
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    DefaultRetryMaxDelay,
+		Jitter:      DefaultRetryJitter,
+	}
+}
+
+// attempts is a function to get total number of attempts
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+// backoff is a function to get delay before the next attempt
+// It will use the delay requested by OpenAI through response header when available
+func (p *RetryPolicy) backoff(attempt int, response *http.Response) time.Duration {
+	delay, ok := delayFromHeader(response)
+	if !ok {
+		delay = p.BaseDelay * time.Duration(math.Pow(2, float64(attempt-1)))
+
+		if p.Jitter > 0 {
+			delay -= time.Duration(rand.Float64() * math.Min(p.Jitter, 1) * float64(delay)) //nolint: gosec
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay
+}
+
+// delayFromHeader is a function to get delay from Retry-After and x-ratelimit-reset-* header
+func delayFromHeader(response *http.Response) (time.Duration, bool) {
+	if response == nil {
+		return 0, false
+	}
+
+	if ms, err := strconv.ParseFloat(response.Header.Get(HeaderRetryAfterMs), 64); err == nil {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+
+	if retryAfter := response.Header.Get(HeaderRetryAfter); retryAfter != "" {
+		if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second)), true
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return time.Until(date), true
+		}
+	}
+
+	var (
+		delay time.Duration
+		found bool
+	)
+
+	resets := map[string]string{
+		HeaderRateLimitRemainingRequests: HeaderRateLimitResetRequests,
+		HeaderRateLimitRemainingTokens:   HeaderRateLimitResetTokens,
+	}
+
+	for remainingHeader, resetHeader := range resets {
+		if response.Header.Get(remainingHeader) != rateLimitExhausted {
+			continue
+		}
+
+		reset, err := time.ParseDuration(response.Header.Get(resetHeader))
+		if err != nil {
+			continue
+		}
+
+		if reset > delay {
+			delay = reset
+		}
+
+		found = true
+	}
+
+	return delay, found
+}
+
+// shouldRetry is a function to check if the failed attempt can be retried
+// Idempotent methods are retried on network error, 408, 409, 429 and 5xx
+// POST is retried only when OpenAI did not process it: 429 or failed to connect,
+// unless the request carries an Idempotency-Key header
+func shouldRetry(request *http.Request, response *http.Response, err error) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+
+	safe := isIdempotent(request)
+
+	if err != nil {
+		return safe || isConnectError(err)
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusRequestTimeout, http.StatusConflict:
+		return safe
+	default:
+		return safe && response.StatusCode >= http.StatusInternalServerError
+	}
+}
+
+// isIdempotent is a function to check if request can be sent more than once without side effect
+func isIdempotent(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return request.Header.Get(HeaderIdempotencyKey) != ""
+	}
+}
+
+// isConnectError is a function to check if error happened before request was sent to server
+func isConnectError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+
+	return errors.As(err, &dnsErr)
+}
+
+// replayableBody is a function to make request body readable on every attempt
+// Only in-memory body can be replayed, other readers are sent once
+func replayableBody(body io.Reader) (func() io.Reader, bool) {
+	var data []byte
+
+	switch b := body.(type) {
+	case nil:
+		return func() io.Reader { return nil }, true
+	case *bytes.Buffer:
+		data = b.Bytes()
+	case *bytes.Reader:
+		data = make([]byte, b.Len())
+		_, _ = b.Read(data)
+	case *strings.Reader:
+		data = make([]byte, b.Len())
+		_, _ = b.Read(data)
+	default:
+		return func() io.Reader { return body }, false
+	}
+
+	return func() io.Reader { return bytes.NewReader(data) }, true
+}
+
+// sleep is a function to wait for the given delay or until context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
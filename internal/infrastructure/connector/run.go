@@ -0,0 +1,117 @@
+package connector
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Run lifecycle of OpenAI API:
+// queued -> in_progress -> requires_action -> in_progress -> completed
+// Any pending status can end in failed, cancelling -> cancelled, expired or incomplete
+const (
+	OpenAIStatusQueued         = "queued"
+	OpenAIStatusInProgress     = "in_progress"
+	OpenAIStatusRequiresAction = "requires_action"
+	OpenAIStatusCancelling     = "cancelling"
+	OpenAIStatusCancelled      = "cancelled"
+	OpenAIStatusFailed         = "failed"
+	OpenAIStatusCompleted      = "completed"
+	OpenAIStatusExpired        = "expired"
+	OpenAIStatusIncomplete     = "incomplete"
+)
+
+var (
+	ErrRunFailed     = errors.New("run failed")
+	ErrRunCancelled  = errors.New("run cancelled")
+	ErrRunExpired    = errors.New("run expired")
+	ErrRunIncomplete = errors.New("run incomplete")
+)
+
+// RunError is an error of run that ends without completing
+// It wraps ErrRunFailed, ErrRunCancelled, ErrRunExpired or ErrRunIncomplete
+type RunError struct {
+	RunID   string
+	Status  string
+	Code    string
+	Message string
+	err     error
+}
+
+// Error is a function to implement error interface
+func (e *RunError) Error() string {
+	message := fmt.Sprintf("%s: run_id=%s", e.err, e.RunID)
+
+	if e.Code != "" {
+		message += " code=" + e.Code
+	}
+
+	if e.Message != "" {
+		message += ": " + e.Message
+	}
+
+	return message
+}
+
+// Unwrap is a function to get the sentinel error of the run status
+func (e *RunError) Unwrap() error {
+	return e.err
+}
+
+// IsPending is a function to check if the run is still processed by OpenAI
+// Empty status is treated as pending because the run has not been reported yet
+func (r *OpenAIRun) IsPending() bool {
+	switch r.Status {
+	case "", OpenAIStatusQueued, OpenAIStatusInProgress, OpenAIStatusCancelling:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal is a function to check if the run will not change its status anymore
+func (r *OpenAIRun) IsTerminal() bool {
+	return !r.IsPending() && r.Status != OpenAIStatusRequiresAction
+}
+
+// Err is a function to get error of terminal run
+// It will return nil when the run is completed or not terminal yet
+func (r *OpenAIRun) Err() error {
+	runErr := &RunError{
+		RunID:  r.ID,
+		Status: r.Status,
+	}
+
+	switch r.Status {
+	case OpenAIStatusFailed:
+		runErr.err = ErrRunFailed
+
+		if r.LastError != nil {
+			runErr.Code = r.LastError.Code
+			runErr.Message = r.LastError.Message
+		}
+	case OpenAIStatusCancelled:
+		runErr.err = ErrRunCancelled
+	case OpenAIStatusExpired:
+		runErr.err = ErrRunExpired
+	case OpenAIStatusIncomplete:
+		runErr.err = ErrRunIncomplete
+
+		if r.IncompleteDetails != nil {
+			runErr.Code = r.IncompleteDetails.Reason
+		}
+	default:
+		return nil
+	}
+
+	return runErr
+}
+
+// AsRunError is a function to get RunError from error chain
+func AsRunError(err error) (*RunError, bool) {
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		return runErr, true
+	}
+
+	return nil, false
+}
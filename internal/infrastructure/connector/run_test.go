@@ -0,0 +1,78 @@
+package connector_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"testing"
+)
+
+func TestOpenAIRun_Err(t *testing.T) {
+	type test struct {
+		run          connector.OpenAIRun
+		wantTerminal bool
+		wantErr      error
+		wantCode     string
+	}
+
+	tests := map[string]test{
+		"in progress": {
+			run: connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusInProgress},
+		},
+		"requires action": {
+			run: connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusRequiresAction},
+		},
+		"completed": {
+			run:          connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusCompleted},
+			wantTerminal: true,
+		},
+		"failed": {
+			run: connector.OpenAIRun{
+				ID:        "run-1",
+				Status:    connector.OpenAIStatusFailed,
+				LastError: &connector.RunLastError{Code: "rate_limit_exceeded", Message: "quota"},
+			},
+			wantTerminal: true,
+			wantErr:      connector.ErrRunFailed,
+			wantCode:     "rate_limit_exceeded",
+		},
+		"cancelled": {
+			run:          connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusCancelled},
+			wantTerminal: true,
+			wantErr:      connector.ErrRunCancelled,
+		},
+		"expired": {
+			run:          connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusExpired},
+			wantTerminal: true,
+			wantErr:      connector.ErrRunExpired,
+		},
+		"incomplete": {
+			run: connector.OpenAIRun{
+				ID:                "run-1",
+				Status:            connector.OpenAIStatusIncomplete,
+				IncompleteDetails: &connector.IncompleteDetails{Reason: "max_completion_tokens"},
+			},
+			wantTerminal: true,
+			wantErr:      connector.ErrRunIncomplete,
+			wantCode:     "max_completion_tokens",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.wantTerminal, tt.run.IsTerminal())
+
+			err := tt.run.Err()
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			runErr, ok := connector.AsRunError(err)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, runErr.Code)
+			assert.Equal(t, "run-1", runErr.RunID)
+		})
+	}
+}
@@ -4,38 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
 // Send is a function to send HTTP Request
+// Multipart request is sent with the client without timeout, so uploading a large file is bounded only by the context
 func (c *connector) Send(ctx context.Context, requestOption *RequestOption, result any) error {
-	// Set HTTP Request Parameter
-	url := fmt.Sprintf("%s%s", c.openai.BaseURL, requestOption.URL)
-	log.Infow("Request Created:", "url", url, "method", requestOption.Method)
-
-	request, err := http.NewRequestWithContext(
-		ctx,
-		requestOption.Method,
-		url,
-		requestOption.Body,
-	)
-	if err != nil {
-		return err
-	}
-
-	// Set HTTP Request Header
-	for k, v := range c.openai.Header {
-		request.Header.Set(k, v)
+	client := c.client
+	if len(requestOption.Multipart) > 0 {
+		client = c.streamClient
 	}
 
-	for k, v := range requestOption.CustomHeader {
-		request.Header.Set(k, v)
-	}
-
-	// Do HTTP Request
-	response, err := http.DefaultClient.Do(request)
+	response, err := c.do(ctx, requestOption, client)
 	if err != nil {
 		return err
 	}
@@ -49,3 +32,112 @@ func (c *connector) Send(ctx context.Context, requestOption *RequestOption, resu
 
 	return nil
 }
+
+// do is a function to send HTTP Request with retry policy using the given client
+// It will return the HTTP Response only when the status is 2xx, the caller must close the body
+func (c *connector) do(ctx context.Context, requestOption *RequestOption, client *http.Client) (*http.Response, error) {
+	// Set HTTP Request Parameter
+	url := fmt.Sprintf("%s%s", c.openai.BaseURL, requestOption.URL)
+	log.Infow("Request Created:", "url", url, "method", requestOption.Method)
+
+	ctx, cancel := c.withOverallTimeout(ctx, client)
+
+	body, replayable := replayableBody(requestOption.Body)
+
+	var (
+		contentType   string
+		contentLength int64
+	)
+
+	if len(requestOption.Multipart) > 0 {
+		var multipartBody io.ReadCloser
+
+		multipartBody, contentType, contentLength = newMultipartBody(requestOption.Multipart)
+		body, replayable = func() io.Reader { return multipartBody }, false
+	}
+
+	maxAttempts := c.openai.Retry.attempts()
+	if !replayable {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		requestBody := body()
+
+		request, err := http.NewRequestWithContext(
+			ctx,
+			requestOption.Method,
+			url,
+			requestBody,
+		)
+		if err != nil {
+			if closer, ok := requestBody.(io.Closer); ok {
+				closer.Close()
+			}
+
+			cancel()
+
+			return nil, err
+		}
+
+		if contentLength > 0 {
+			request.ContentLength = contentLength
+		}
+
+		// Set HTTP Request Header
+		for k, v := range c.openai.Header {
+			request.Header.Set(k, v)
+		}
+
+		if contentType != "" {
+			request.Header.Set(HeaderContentType, contentType)
+		}
+
+		for k, v := range requestOption.CustomHeader {
+			request.Header.Set(k, v)
+		}
+
+		// Do HTTP Request
+		response, err := client.Do(request)
+
+		if err == nil && response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+			response.Body = &cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+			return response, nil
+		}
+
+		if attempt >= maxAttempts || !shouldRetry(request, response, err) {
+			defer cancel()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, c.fail(url, response)
+		}
+
+		delay := c.openai.Retry.backoff(attempt, response)
+		if response != nil {
+			// Drain failed HTTP Response so the connection can be reused
+			_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxErrorBodySize))
+			response.Body.Close()
+		}
+
+		log.Warnw("Request Retry:", "url", url, "attempt", attempt, "delay", delay, "error", err)
+
+		if err := sleep(ctx, delay); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+}
+
+// fail is a function to convert non-2xx HTTP Response into APIError
+func (c *connector) fail(url string, response *http.Response) error {
+	defer response.Body.Close()
+
+	// Check HTTP Response Status
+	apiErr := newAPIError(response)
+	log.Warnw("Request Failed:", "url", url, "status", apiErr.HTTPStatus, "request_id", apiErr.RequestID)
+
+	return apiErr
+}
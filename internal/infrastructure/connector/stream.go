@@ -0,0 +1,284 @@
+package connector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+)
+
+const (
+	EventThreadCreated     = "thread.created"
+	EventRunCreated        = "thread.run.created"
+	EventRunQueued         = "thread.run.queued"
+	EventRunInProgress     = "thread.run.in_progress"
+	EventRunRequiresAction = "thread.run.requires_action"
+	EventRunCompleted      = "thread.run.completed"
+	EventRunFailed         = "thread.run.failed"
+	EventRunCancelling     = "thread.run.cancelling"
+	EventRunCancelled      = "thread.run.cancelled"
+	EventRunExpired        = "thread.run.expired"
+	EventRunStepCreated    = "thread.run.step.created"
+	EventRunStepInProgress = "thread.run.step.in_progress"
+	EventRunStepDelta      = "thread.run.step.delta"
+	EventRunStepCompleted  = "thread.run.step.completed"
+	EventRunStepFailed     = "thread.run.step.failed"
+	EventRunStepCancelled  = "thread.run.step.cancelled"
+	EventRunStepExpired    = "thread.run.step.expired"
+	EventMessageCreated    = "thread.message.created"
+	EventMessageInProgress = "thread.message.in_progress"
+	EventMessageDelta      = "thread.message.delta"
+	EventMessageCompleted  = "thread.message.completed"
+	EventMessageIncomplete = "thread.message.incomplete"
+	EventError             = "error"
+	EventDone              = "done"
+)
+
+const (
+	eventRunPrefix     = "thread.run."
+	eventRunStepPrefix = "thread.run.step."
+	eventMessagePrefix = "thread.message."
+	eventDoneData      = "[DONE]"
+
+	// maxEventSize is the maximum size of a single line in the event stream
+	maxEventSize    = 1 << 20
+	eventBufferSize = 1 << 12
+)
+
+var (
+	ErrNotEventStream = errors.New("response is not an event stream")
+	ErrEventType      = errors.New("unexpected event type")
+)
+
+// Event is a struct of Server-Sent Event from OpenAI API
+type Event struct {
+	ID   string
+	Type string
+	Data json.RawMessage
+}
+
+// Decode is a function to decode event data into the given value
+func (e *Event) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// IsRun is a function to check if event data is a run object
+func (e *Event) IsRun() bool {
+	return strings.HasPrefix(e.Type, eventRunPrefix) && !strings.HasPrefix(e.Type, eventRunStepPrefix)
+}
+
+// IsMessage is a function to check if event data is a message object
+func (e *Event) IsMessage() bool {
+	return strings.HasPrefix(e.Type, eventMessagePrefix) && e.Type != EventMessageDelta
+}
+
+// Run is a function to decode thread.run.* event
+func (e *Event) Run() (*OpenAIRun, error) {
+	if !e.IsRun() {
+		return nil, fmt.Errorf("%w: %s is not a run event", ErrEventType, e.Type)
+	}
+
+	var run OpenAIRun
+	if err := e.Decode(&run); err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// Message is a function to decode thread.message.* event except the delta
+func (e *Event) Message() (*repository.Message, error) {
+	if !e.IsMessage() {
+		return nil, fmt.Errorf("%w: %s is not a message event", ErrEventType, e.Type)
+	}
+
+	var message repository.Message
+	if err := e.Decode(&message); err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// MessageDelta is a function to decode thread.message.delta event
+func (e *Event) MessageDelta() (*OpenAIMessageDelta, error) {
+	if e.Type != EventMessageDelta {
+		return nil, fmt.Errorf("%w: %s is not a message delta event", ErrEventType, e.Type)
+	}
+
+	var delta OpenAIMessageDelta
+	if err := e.Decode(&delta); err != nil {
+		return nil, err
+	}
+
+	return &delta, nil
+}
+
+// Stream is a function to send HTTP Request and read the response as Server-Sent Events
+func (c *connector) Stream(ctx context.Context, requestOption *RequestOption) (EventStream, error) {
+	streamRequestOption := *requestOption
+	streamRequestOption.CustomHeader = map[string]string{
+		HeaderAccept: EventStreamMIME,
+	}
+
+	for k, v := range requestOption.CustomHeader {
+		streamRequestOption.CustomHeader[k] = v
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	response, err := c.do(ctx, &streamRequestOption, c.streamClient)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get(HeaderContentType))
+	if mediaType != EventStreamMIME {
+		response.Body.Close()
+		cancel()
+
+		return nil, fmt.Errorf("%w: content type %q", ErrNotEventStream, mediaType)
+	}
+
+	log.Infow("Stream Opened:", "url", requestOption.URL)
+
+	return newEventStream(ctx, cancel, response.Body), nil
+}
+
+// eventStream is a struct to parse text/event-stream body
+type eventStream struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+	done    bool
+	once    sync.Once
+}
+
+func newEventStream(ctx context.Context, cancel context.CancelFunc, body io.ReadCloser) *eventStream {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, eventBufferSize), maxEventSize)
+
+	return &eventStream{
+		ctx:     ctx,
+		cancel:  cancel,
+		body:    body,
+		scanner: scanner,
+	}
+}
+
+// Recv is a function to read the next event from the stream
+// Comment and empty event are skipped, error event is returned as APIError
+func (s *eventStream) Recv() (*Event, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	var (
+		event Event
+		data  bytes.Buffer
+		seen  bool
+	)
+
+	for s.scanner.Scan() {
+		line := strings.TrimSuffix(s.scanner.Text(), "\r")
+
+		// Blank line dispatches the event
+		if line == "" {
+			if !seen {
+				continue
+			}
+
+			event.Data = data.Bytes()
+
+			return s.dispatch(&event)
+		}
+
+		// Line started with colon is a comment, e.g. keep-alive
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		seen = true
+
+		switch field {
+		case "event":
+			event.Type = value
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+
+			data.WriteString(value)
+		case "id":
+			event.ID = value
+		}
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	s.done = true
+
+	// Dispatch the last event when the stream is closed without trailing blank line
+	if seen {
+		event.Data = data.Bytes()
+		return s.dispatch(&event)
+	}
+
+	return nil, io.EOF
+}
+
+// dispatch is a function to return parsed event to the consumer
+func (s *eventStream) dispatch(event *Event) (*Event, error) {
+	if event.Type == "" && string(event.Data) == eventDoneData {
+		event.Type = EventDone
+	}
+
+	switch event.Type {
+	case EventDone:
+		s.done = true
+		s.Close()
+	case EventError:
+		s.done = true
+		s.Close()
+
+		apiErr := &APIError{}
+		if err := json.Unmarshal(event.Data, apiErr); err != nil || apiErr.Message == "" {
+			apiErr.Message = string(event.Data)
+		}
+
+		return nil, apiErr
+	}
+
+	return event, nil
+}
+
+// Close is a function to close the stream and release the connection
+func (s *eventStream) Close() error {
+	var err error
+
+	s.once.Do(func() {
+		err = s.body.Close()
+		s.cancel()
+	})
+
+	return err
+}
@@ -0,0 +1,152 @@
+package connector_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestConnector_Stream(t *testing.T) {
+	type test struct {
+		fields     fields
+		ctx        context.Context
+		cancel     context.CancelFunc
+		wantEvents []string
+		wantText   string
+		wantErr    error
+		errFn      func(err error) bool
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"success": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, connector.EventStreamMIME, r.Header.Get(connector.HeaderAccept))
+				assert.Equal(t, connector.AssistantV1, r.Header.Get(connector.HeaderKeyOpenAIBeta))
+
+				w.Header().Set(connector.HeaderContentType, "text/event-stream; charset=utf-8")
+				_, _ = io.WriteString(w, ": keep-alive\n\n"+
+					"event: thread.run.created\ndata: {\"id\":\"run-1\",\"status\":\"queued\"}\n\n"+
+					"event: thread.message.delta\r\ndata: {\"id\":\"msg-1\",\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\"Hello\"}}]}}\r\n\r\n"+
+					"event: thread.message.delta\ndata: {\"id\":\"msg-1\",\"delta\":{\"content\":[{\"index\":0,\"type\":\"text\",\"text\":{\"value\":\" World\"}}]}}\n\n"+
+					"event: thread.run.completed\ndata: {\"id\":\"run-1\",\"status\":\"completed\"}\n\n"+
+					"event: done\ndata: [DONE]\n\n")
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				ctx:    context.Background(),
+				wantEvents: []string{
+					connector.EventRunCreated,
+					connector.EventMessageDelta,
+					connector.EventMessageDelta,
+					connector.EventRunCompleted,
+					connector.EventDone,
+				},
+				wantText: "Hello World",
+			}
+		},
+		"error event": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(connector.HeaderContentType, connector.EventStreamMIME)
+				_, _ = io.WriteString(w, "event: error\ndata: {\"code\":\"server_error\",\"message\":\"boom\"}\n\n")
+			})
+
+			return test{
+				fields:  fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				ctx:     context.Background(),
+				wantErr: &connector.APIError{Code: "server_error", Message: "boom"},
+			}
+		},
+		"not an event stream": func(t *testing.T) test {
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(connector.HeaderContentType, "application/json")
+				_, _ = io.WriteString(w, `{}`)
+			})
+
+			return test{
+				fields: fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				ctx:    context.Background(),
+				errFn: func(err error) bool {
+					return assert.ErrorIs(t, err, connector.ErrNotEventStream)
+				},
+			}
+		},
+		"cancelled context": func(t *testing.T) test {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			server := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(connector.HeaderContentType, connector.EventStreamMIME)
+				_, _ = io.WriteString(w, "event: thread.run.created\ndata: {\"id\":\"run-1\"}\n\n")
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			})
+
+			return test{
+				fields:     fields{openai: &connector.OpenAI{BaseURL: server.URL}},
+				ctx:        ctx,
+				cancel:     cancel,
+				wantEvents: []string{connector.EventRunCreated},
+				wantErr:    context.Canceled,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			sut := sut(tt.fields)
+
+			stream, err := sut.Stream(tt.ctx, &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-1/runs",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			})
+			if tt.errFn != nil {
+				assert.True(t, tt.errFn(err))
+				return
+			}
+
+			require.NoError(t, err)
+			defer stream.Close()
+
+			var (
+				events []string
+				text   string
+			)
+
+			for {
+				event, err := stream.Recv()
+				if err != nil {
+					if tt.wantErr != nil {
+						assert.Equal(t, tt.wantErr, err)
+					} else {
+						assert.ErrorIs(t, err, io.EOF)
+					}
+
+					break
+				}
+
+				events = append(events, event.Type)
+
+				// Client is gone after the first event
+				if tt.cancel != nil {
+					tt.cancel()
+				}
+
+				if delta, err := event.MessageDelta(); err == nil {
+					text += delta.Text()
+				}
+			}
+
+			assert.Equal(t, tt.wantEvents, events)
+			assert.Equal(t, tt.wantText, text)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	boltOpenTimeout = 5 * time.Second
+	boltFileMode    = 0o600
+)
+
+var (
+	bucketConversations       = []byte("conversations")
+	bucketConversationThreads = []byte("conversation_threads")
+	bucketConversationOwners  = []byte("conversation_owners")
+)
+
+type boltConversationRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltConversationRepository is a function to create conversation repository stored in embedded BoltDB file
+// Conversations survive process restart, the file can be opened by one process only
+func NewBoltConversationRepository(path string) (repository.ConversationRepository, error) {
+	db, err := bolt.Open(path, boltFileMode, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{bucketConversations, bucketConversationThreads, bucketConversationOwners} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltConversationRepository{
+		db: db,
+	}, nil
+}
+
+// ownerKey is a function to get key of owner index, the key is sortable by owner
+func ownerKey(ownerID, id string) []byte {
+	return []byte(ownerID + "\x00" + id)
+}
+
+// Create is a function to store new conversation
+func (r *boltConversationRepository) Create(_ context.Context, conversation *repository.Conversation) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		conversations := tx.Bucket(bucketConversations)
+		if conversations.Get([]byte(conversation.ID)) != nil {
+			return repository.ErrConversationExists
+		}
+
+		conversation.CreatedAt = now()
+		conversation.UpdatedAt = conversation.CreatedAt
+
+		return r.put(tx, conversation)
+	})
+}
+
+// Get is a function to get conversation by ID
+func (r *boltConversationRepository) Get(_ context.Context, id string) (*repository.Conversation, error) {
+	var conversation *repository.Conversation
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		conversation, err = r.get(tx, []byte(id))
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// GetByThreadID is a function to get conversation by OpenAI thread ID
+func (r *boltConversationRepository) GetByThreadID(_ context.Context, threadID string) (*repository.Conversation, error) {
+	var conversation *repository.Conversation
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(bucketConversationThreads).Get([]byte(threadID))
+		if id == nil {
+			return repository.ErrConversationNotFound
+		}
+
+		var err error
+		conversation, err = r.get(tx, id)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// List is a function to get conversations of the owner, the latest updated first
+func (r *boltConversationRepository) List(_ context.Context, ownerID string) ([]repository.Conversation, error) {
+	conversations := make([]repository.Conversation, 0)
+
+	err := r.db.View(func(tx *bolt.Tx) error {
+		prefix := ownerKey(ownerID, "")
+		cursor := tx.Bucket(bucketConversationOwners).Cursor()
+
+		for k, id := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, id = cursor.Next() {
+			conversation, err := r.get(tx, id)
+			if err != nil {
+				return err
+			}
+
+			conversations = append(conversations, *conversation)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortConversations(conversations)
+
+	return conversations, nil
+}
+
+// Update is a function to replace stored conversation
+func (r *boltConversationRepository) Update(_ context.Context, conversation *repository.Conversation) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := r.get(tx, []byte(conversation.ID))
+		if err != nil {
+			return err
+		}
+
+		if err := r.deleteIndex(tx, stored); err != nil {
+			return err
+		}
+
+		conversation.CreatedAt = stored.CreatedAt
+		conversation.UpdatedAt = now()
+
+		return r.put(tx, conversation)
+	})
+}
+
+// Modify is a function to change stored conversation within one transaction
+func (r *boltConversationRepository) Modify(
+	_ context.Context,
+	id string,
+	change func(conversation *repository.Conversation),
+) (*repository.Conversation, error) {
+	var conversation *repository.Conversation
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := r.get(tx, []byte(id))
+		if err != nil {
+			return err
+		}
+
+		conversation = stored
+		ownerID, threadID := stored.OwnerID, stored.ThreadID
+
+		change(conversation)
+		keepIdentity(conversation, id, ownerID, threadID)
+		conversation.UpdatedAt = now()
+
+		return r.put(tx, conversation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// Delete is a function to delete conversation by ID
+func (r *boltConversationRepository) Delete(_ context.Context, id string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		stored, err := r.get(tx, []byte(id))
+		if err != nil {
+			return err
+		}
+
+		if err := r.deleteIndex(tx, stored); err != nil {
+			return err
+		}
+
+		return tx.Bucket(bucketConversations).Delete([]byte(id))
+	})
+}
+
+// Close is a function to close the BoltDB file
+func (r *boltConversationRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *boltConversationRepository) get(tx *bolt.Tx, id []byte) (*repository.Conversation, error) {
+	data := tx.Bucket(bucketConversations).Get(id)
+	if data == nil {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	var conversation repository.Conversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+func (r *boltConversationRepository) put(tx *bolt.Tx, conversation *repository.Conversation) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return err
+	}
+
+	id := []byte(conversation.ID)
+
+	if err := tx.Bucket(bucketConversations).Put(id, data); err != nil {
+		return err
+	}
+
+	if conversation.ThreadID != "" {
+		if err := tx.Bucket(bucketConversationThreads).Put([]byte(conversation.ThreadID), id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(bucketConversationOwners).Put(ownerKey(conversation.OwnerID, conversation.ID), id)
+}
+
+func (r *boltConversationRepository) deleteIndex(tx *bolt.Tx, conversation *repository.Conversation) error {
+	if conversation.ThreadID != "" {
+		if err := tx.Bucket(bucketConversationThreads).Delete([]byte(conversation.ThreadID)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(bucketConversationOwners).Delete(ownerKey(conversation.OwnerID, conversation.ID))
+}
@@ -0,0 +1,131 @@
+package storage_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/storage"
+	"path/filepath"
+	"testing"
+)
+
+func conversationSuts(t *testing.T) map[string]func() repository.ConversationRepository {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "assistant.db")
+
+	return map[string]func() repository.ConversationRepository{
+		"memory": func() repository.ConversationRepository {
+			return storage.NewMemoryConversationRepository()
+		},
+		"bolt": func() repository.ConversationRepository {
+			conversationRepository, err := storage.NewBoltConversationRepository(path)
+			require.NoError(t, err)
+
+			return conversationRepository
+		},
+	}
+}
+
+func TestConversationRepository(t *testing.T) {
+	for name, sutFn := range conversationSuts(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			sut := sutFn()
+			defer sut.Close()
+
+			conversation := &repository.Conversation{
+				ID:          "conversation-1",
+				OwnerID:     "user-1",
+				ThreadID:    "thread-1",
+				AssistantID: "asst-1",
+				Title:       "Hello",
+				Metadata:    map[string]string{"source": "web"},
+			}
+
+			require.NoError(t, sut.Create(ctx, conversation))
+			assert.NotZero(t, conversation.CreatedAt)
+			assert.ErrorIs(t, sut.Create(ctx, conversation), repository.ErrConversationExists)
+
+			require.NoError(t, sut.Create(ctx, &repository.Conversation{ID: "conversation-2", OwnerID: "user-1", ThreadID: "thread-2"}))
+			require.NoError(t, sut.Create(ctx, &repository.Conversation{ID: "conversation-3", OwnerID: "user-2", ThreadID: "thread-3"}))
+
+			got, err := sut.Get(ctx, "conversation-1")
+			require.NoError(t, err)
+			assert.Equal(t, conversation, got)
+
+			got, err = sut.GetByThreadID(ctx, "thread-1")
+			require.NoError(t, err)
+			assert.Equal(t, conversation, got)
+
+			conversations, err := sut.List(ctx, "user-1")
+			require.NoError(t, err)
+			assert.Len(t, conversations, 2)
+
+			got.ThreadID = "thread-4"
+			got.Title = "Renamed"
+			got.Tags = []string{"work"}
+			got.Pinned = true
+			require.NoError(t, sut.Update(ctx, got))
+
+			_, err = sut.GetByThreadID(ctx, "thread-1")
+			assert.ErrorIs(t, err, repository.ErrConversationNotFound)
+
+			updated, err := sut.GetByThreadID(ctx, "thread-4")
+			require.NoError(t, err)
+			assert.Equal(t, "Renamed", updated.Title)
+			assert.Equal(t, []string{"work"}, updated.Tags)
+			assert.True(t, updated.Pinned)
+			assert.Equal(t, conversation.CreatedAt, updated.CreatedAt)
+
+			modified, err := sut.Modify(ctx, "conversation-1", func(conversation *repository.Conversation) {
+				conversation.FileIDs = append(conversation.FileIDs, "file-1")
+				conversation.ThreadID = "thread-5"
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"file-1"}, modified.FileIDs)
+			assert.Equal(t, "thread-4", modified.ThreadID)
+			assert.Equal(t, "Renamed", modified.Title)
+
+			got, err = sut.GetByThreadID(ctx, "thread-4")
+			require.NoError(t, err)
+			assert.Equal(t, modified, got)
+
+			_, err = sut.Modify(ctx, "conversation-9", func(*repository.Conversation) {})
+			assert.ErrorIs(t, err, repository.ErrConversationNotFound)
+
+			require.NoError(t, sut.Delete(ctx, "conversation-1"))
+			assert.ErrorIs(t, sut.Delete(ctx, "conversation-1"), repository.ErrConversationNotFound)
+			assert.ErrorIs(t, sut.Update(ctx, got), repository.ErrConversationNotFound)
+
+			_, err = sut.Get(ctx, "conversation-1")
+			assert.ErrorIs(t, err, repository.ErrConversationNotFound)
+
+			conversations, err = sut.List(ctx, "user-1")
+			require.NoError(t, err)
+			require.Len(t, conversations, 1)
+			assert.Equal(t, "conversation-2", conversations[0].ID)
+		})
+	}
+}
+
+func TestBoltConversationRepository_Restart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "assistant.db")
+
+	sut, err := storage.NewBoltConversationRepository(path)
+	require.NoError(t, err)
+
+	require.NoError(t, sut.Create(ctx, &repository.Conversation{ID: "conversation-1", OwnerID: "user-1", ThreadID: "thread-1"}))
+	require.NoError(t, sut.Close())
+
+	sut, err = storage.NewBoltConversationRepository(path)
+	require.NoError(t, err)
+	defer sut.Close()
+
+	got, err := sut.GetByThreadID(ctx, "thread-1")
+	require.NoError(t, err)
+	assert.Equal(t, "conversation-1", got.ID)
+}
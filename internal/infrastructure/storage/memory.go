@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+)
+
+type memoryConversationRepository struct {
+	mu            sync.RWMutex
+	conversations map[string]*repository.Conversation
+	threads       map[string]string
+}
+
+// NewMemoryConversationRepository is a function to create conversation repository kept in memory
+// Conversations are lost when the process is restarted
+func NewMemoryConversationRepository() repository.ConversationRepository {
+	return &memoryConversationRepository{
+		conversations: make(map[string]*repository.Conversation),
+		threads:       make(map[string]string),
+	}
+}
+
+// Create is a function to store new conversation
+func (r *memoryConversationRepository) Create(_ context.Context, conversation *repository.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.conversations[conversation.ID]; ok {
+		return repository.ErrConversationExists
+	}
+
+	conversation.CreatedAt = now()
+	conversation.UpdatedAt = conversation.CreatedAt
+
+	r.conversations[conversation.ID] = cloneConversation(conversation)
+	r.threads[conversation.ThreadID] = conversation.ID
+
+	return nil
+}
+
+// Get is a function to get conversation by ID
+func (r *memoryConversationRepository) Get(_ context.Context, id string) (*repository.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversation, ok := r.conversations[id]
+	if !ok {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	return cloneConversation(conversation), nil
+}
+
+// GetByThreadID is a function to get conversation by OpenAI thread ID
+func (r *memoryConversationRepository) GetByThreadID(ctx context.Context, threadID string) (*repository.Conversation, error) {
+	r.mu.RLock()
+	id, ok := r.threads[threadID]
+	r.mu.RUnlock()
+
+	if !ok || threadID == "" {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	return r.Get(ctx, id)
+}
+
+// List is a function to get conversations of the owner, the latest updated first
+func (r *memoryConversationRepository) List(_ context.Context, ownerID string) ([]repository.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversations := make([]repository.Conversation, 0)
+
+	for _, conversation := range r.conversations {
+		if conversation.OwnerID == ownerID {
+			conversations = append(conversations, *cloneConversation(conversation))
+		}
+	}
+
+	sortConversations(conversations)
+
+	return conversations, nil
+}
+
+// Update is a function to replace stored conversation
+func (r *memoryConversationRepository) Update(_ context.Context, conversation *repository.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.conversations[conversation.ID]
+	if !ok {
+		return repository.ErrConversationNotFound
+	}
+
+	conversation.CreatedAt = stored.CreatedAt
+	conversation.UpdatedAt = now()
+
+	delete(r.threads, stored.ThreadID)
+
+	r.conversations[conversation.ID] = cloneConversation(conversation)
+	r.threads[conversation.ThreadID] = conversation.ID
+
+	return nil
+}
+
+// Modify is a function to change stored conversation under the lock
+func (r *memoryConversationRepository) Modify(
+	_ context.Context,
+	id string,
+	change func(conversation *repository.Conversation),
+) (*repository.Conversation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.conversations[id]
+	if !ok {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	conversation := cloneConversation(stored)
+	change(conversation)
+	keepIdentity(conversation, stored.ID, stored.OwnerID, stored.ThreadID)
+	conversation.UpdatedAt = now()
+
+	r.conversations[id] = cloneConversation(conversation)
+
+	return conversation, nil
+}
+
+// Delete is a function to delete conversation by ID
+func (r *memoryConversationRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.conversations[id]
+	if !ok {
+		return repository.ErrConversationNotFound
+	}
+
+	delete(r.threads, stored.ThreadID)
+	delete(r.conversations, id)
+
+	return nil
+}
+
+// Close is a function to release the repository, memory repository has nothing to release
+func (r *memoryConversationRepository) Close() error {
+	return nil
+}
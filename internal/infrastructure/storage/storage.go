@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+)
+
+// now is a function to get current unix time, it is replaced in test
+var now = func() int64 {
+	return time.Now().Unix()
+}
+
+// cloneConversation is a function to copy conversation so the stored value cannot be changed by the caller
+func cloneConversation(conversation *repository.Conversation) *repository.Conversation {
+	clone := *conversation
+
+	if conversation.Metadata != nil {
+		clone.Metadata = make(map[string]string, len(conversation.Metadata))
+		for k, v := range conversation.Metadata {
+			clone.Metadata[k] = v
+		}
+	}
+
+	clone.FileIDs = slices.Clone(conversation.FileIDs)
+	clone.Tags = slices.Clone(conversation.Tags)
+
+	return &clone
+}
+
+// keepIdentity is a function to restore the fields that Modify cannot change, so the indexes stay valid
+func keepIdentity(conversation *repository.Conversation, id, ownerID, threadID string) {
+	conversation.ID = id
+	conversation.OwnerID = ownerID
+	conversation.ThreadID = threadID
+}
+
+// sortConversations is a function to sort conversation by the latest update
+func sortConversations(conversations []repository.Conversation) {
+	sort.SliceStable(conversations, func(i, j int) bool {
+		if conversations[i].UpdatedAt == conversations[j].UpdatedAt {
+			return conversations[i].ID > conversations[j].ID
+		}
+
+		return conversations[i].UpdatedAt > conversations[j].UpdatedAt
+	})
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+)
+
+// fileContentURL is the path to download file of the thread from this API, it must follow the thread file route
+// Only the owner of the thread can download it, see messagesFileIDs for the files recorded in the thread
+const fileContentURL = "/api/v1/threads/%s/files/%s/content"
+
+// resolveMessage is a function to resolve message content into what client can show without calling OpenAI
+// Citations are replaced by numbered marker [n] with footnote, cited again the same quote reuses its number,
+// file paths of code interpreter, e.g. sandbox:/mnt/data/chart.png, are replaced by the download link,
+// and image files get their download link
+// Numbering continues across text parts, so every marker of the message is unique
+func resolveMessage(threadID string, message *repository.Message) {
+	numbers := make(map[repository.FileCitation]int)
+
+	for i := range message.Content {
+		content := &message.Content[i]
+
+		switch {
+		case content.Type == repository.ContentTypeText && content.Text != nil:
+			resolveText(threadID, content.Text, numbers)
+		case content.Type == repository.ContentTypeImageFile && content.ImageFile != nil:
+			content.ImageFile.URL = fileURL(threadID, content.ImageFile.FileID)
+		}
+	}
+}
+
+// resolveText is a function to resolve annotations of text part into footnotes and download links
+// numbers is the footnote number of every citation resolved in the message so far
+func resolveText(threadID string, text *repository.TextContent, numbers map[repository.FileCitation]int) {
+	text.Footnotes = nil
+
+	for _, annotation := range text.Annotations {
+		if annotation.Text == "" {
+			continue
+		}
+
+		switch {
+		case annotation.Type == repository.AnnotationTypeFileCitation && annotation.FileCitation != nil:
+			citation := *annotation.FileCitation
+
+			number, ok := numbers[citation]
+			if !ok {
+				number = len(numbers) + 1
+				numbers[citation] = number
+			}
+
+			if !hasFootnote(text.Footnotes, number) {
+				text.Footnotes = append(text.Footnotes, repository.Footnote{
+					Number: number,
+					FileID: citation.FileID,
+					Quote:  citation.Quote,
+					URL:    fileURL(threadID, citation.FileID),
+				})
+			}
+
+			text.Value = strings.Replace(text.Value, annotation.Text, fmt.Sprintf("[%d]", number), 1)
+		case annotation.Type == repository.AnnotationTypeFilePath && annotation.FilePath != nil:
+			text.Value = strings.Replace(text.Value, annotation.Text, fileURL(threadID, annotation.FilePath.FileID), 1)
+		}
+	}
+}
+
+// hasFootnote is a function to check whether footnote with the number is already in the list
+func hasFootnote(footnotes []repository.Footnote, number int) bool {
+	for _, footnote := range footnotes {
+		if footnote.Number == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fileURL is a function to get the link to download file of the thread from this API
+func fileURL(threadID, fileID string) string {
+	return fmt.Sprintf(fileContentURL, threadID, fileID)
+}
+
+// messagesFileIDs is a function to get every file linked by the messages, they are recorded in the conversation
+// so its owner can download them, e.g. chart and generated file of code interpreter or cited file
+func messagesFileIDs(messages []repository.Message) []string {
+	var fileIDs []string
+
+	for _, message := range messages {
+		fileIDs = append(fileIDs, message.FileIDS...)
+
+		for _, content := range message.Content {
+			switch {
+			case content.Type == repository.ContentTypeImageFile && content.ImageFile != nil:
+				fileIDs = append(fileIDs, content.ImageFile.FileID)
+			case content.Type == repository.ContentTypeText && content.Text != nil:
+				for _, annotation := range content.Text.Annotations {
+					switch {
+					case annotation.FileCitation != nil:
+						fileIDs = append(fileIDs, annotation.FileCitation.FileID)
+					case annotation.FilePath != nil:
+						fileIDs = append(fileIDs, annotation.FilePath.FileID)
+					}
+				}
+			}
+		}
+	}
+
+	return fileIDs
+}
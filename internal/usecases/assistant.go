@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// CreateAssistant is a function to create assistant in OpenAI
+func (u *assistantUsecase) CreateAssistant(ctx context.Context, assistant *request.Assistant) (*repository.Assistant, error) {
+	if assistant.Model == "" {
+		return nil, fmt.Errorf("%w: model is required", ErrAssistantInvalid)
+	}
+
+	result, err := u.saveAssistant(ctx, "/assistants", assistant)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Assistant Created:", "id", result.ID, "name", result.Name)
+
+	return result, nil
+}
+
+// GetListAssistant is a function to get every assistant from OpenAI API page by page, so the result has no more page
+func (u *assistantUsecase) GetListAssistant(ctx context.Context) (*response.List[repository.Assistant], error) {
+	assistants, err := u.listAllAssistants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if assistants == nil {
+		assistants = make([]repository.Assistant, 0)
+	}
+
+	list := &response.List[repository.Assistant]{Data: assistants}
+	if len(assistants) > 0 {
+		list.LastID = assistants[len(assistants)-1].ID
+	}
+
+	return list, nil
+}
+
+// GetAssistant is a function to get assistant from OpenAI API
+func (u *assistantUsecase) GetAssistant(ctx context.Context, assistantID string) (*repository.Assistant, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    fmt.Sprintf("/assistants/%s", assistantID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	var result *repository.Assistant
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, connector.ErrAssistant
+	}
+
+	return result, nil
+}
+
+// UpdateAssistant is a function to modify assistant in OpenAI, only the fields sent by client are changed
+func (u *assistantUsecase) UpdateAssistant(
+	ctx context.Context,
+	assistantID string,
+	assistant *request.Assistant,
+) (*repository.Assistant, error) {
+	result, err := u.saveAssistant(ctx, fmt.Sprintf("/assistants/%s", assistantID), assistant)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Assistant Updated:", "id", result.ID, "name", result.Name)
+
+	return result, nil
+}
+
+// DeleteAssistant is a function to delete assistant in OpenAI
+func (u *assistantUsecase) DeleteAssistant(ctx context.Context, assistantID string) error {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    fmt.Sprintf("/assistants/%s", assistantID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	var result *connector.OpenAIDeleted
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return err
+	}
+
+	if result == nil || !result.Deleted {
+		return connector.ErrDelete
+	}
+
+	log.Infow("Assistant Deleted:", "id", assistantID)
+
+	return nil
+}
+
+// saveAssistant is a function to validate assistant and send it to OpenAI
+// Create and modify assistant share the same request, only the URL is different
+func (u *assistantUsecase) saveAssistant(ctx context.Context, url string, assistant *request.Assistant) (*repository.Assistant, error) {
+	if err := validateAssistant(assistant); err != nil {
+		return nil, err
+	}
+
+	requestBodyAssistant := connector.RequestAssistant{
+		Model:        assistant.Model,
+		Name:         assistant.Name,
+		Description:  assistant.Description,
+		Instructions: assistant.Instructions,
+		Tools:        assistant.Tools,
+		FileIDs:      assistant.FileIDs,
+		Metadata:     assistant.Metadata,
+	}
+
+	var bufAssistant bytes.Buffer
+	if err := json.NewEncoder(&bufAssistant).Encode(requestBodyAssistant); err != nil {
+		return nil, err
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    url,
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+		Body: &bufAssistant,
+	}
+
+	var result *repository.Assistant
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, connector.ErrAssistant
+	}
+
+	return result, nil
+}
+
+// validateAssistant is a function to check assistant against the limits of OpenAI before it is sent
+func validateAssistant(assistant *request.Assistant) error {
+	switch {
+	case assistant.Name != nil && len(*assistant.Name) > maxAssistantNameLength:
+		return fmt.Errorf("%w: name is longer than %d", ErrAssistantInvalid, maxAssistantNameLength)
+	case assistant.Description != nil && len(*assistant.Description) > maxAssistantDescriptionLength:
+		return fmt.Errorf("%w: description is longer than %d", ErrAssistantInvalid, maxAssistantDescriptionLength)
+	case assistant.Instructions != nil && len(*assistant.Instructions) > maxAssistantInstructionsLength:
+		return fmt.Errorf("%w: instructions is longer than %d", ErrAssistantInvalid, maxAssistantInstructionsLength)
+	case assistant.FileIDs != nil && len(*assistant.FileIDs) > maxAssistantFiles:
+		return fmt.Errorf("%w: more than %d files", ErrAssistantInvalid, maxAssistantFiles)
+	}
+
+	if assistant.Tools != nil {
+		if len(*assistant.Tools) > maxAssistantTools {
+			return fmt.Errorf("%w: more than %d tools", ErrAssistantInvalid, maxAssistantTools)
+		}
+
+		for _, tool := range *assistant.Tools {
+			if err := validateAssistantTool(tool); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := validateMetadata(assistant.Metadata); err != nil {
+		return fmt.Errorf("%w: %w", ErrAssistantInvalid, err)
+	}
+
+	return nil
+}
+
+// validateAssistantTool is a function to check tool type, function tool must have its definition
+func validateAssistantTool(tool repository.AssistantTool) error {
+	switch tool.Type {
+	case connector.OpenAIToolTypeCodeInterpreter, connector.OpenAIToolTypeRetrieval:
+		return nil
+	case connector.OpenAIToolTypeFunction:
+		if tool.Function == nil || tool.Function.Name == "" {
+			return fmt.Errorf("%w: function tool requires function name", ErrAssistantInvalid)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown tool type %q", ErrAssistantInvalid, tool.Type)
+	}
+}
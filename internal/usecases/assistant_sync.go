@@ -0,0 +1,275 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// PlanSync is a function to compare assistant manifest with assistants in OpenAI
+// Assistant is matched by AssistantManagedKey metadata, so only assistant created by sync can be updated or pruned
+// Assistant that is not in the manifest is deleted only when prune is true
+func (u *assistantUsecase) PlanSync(
+	ctx context.Context,
+	manifest *request.AssistantManifest,
+	prune bool,
+) (*response.AssistantSyncPlan, error) {
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	assistants, err := u.listAllAssistants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := make(map[string]*repository.Assistant)
+
+	for i := range assistants {
+		key := assistants[i].Metadata[AssistantManagedKey]
+		if key == "" {
+			continue
+		}
+
+		if other, ok := managed[key]; ok {
+			return nil, fmt.Errorf("%w: key %s is used by %s and %s", ErrAssistantManifest, key, other.ID, assistants[i].ID)
+		}
+
+		managed[key] = &assistants[i]
+	}
+
+	plan := &response.AssistantSyncPlan{
+		Changes: make([]response.AssistantChange, 0, len(manifest.Assistants)),
+	}
+
+	for _, spec := range manifest.Assistants {
+		desired := desiredAssistant(spec)
+
+		current, ok := managed[spec.Key]
+		if !ok {
+			plan.Changes = append(plan.Changes, response.AssistantChange{
+				Action:    response.AssistantChangeCreate,
+				Key:       spec.Key,
+				Name:      spec.Name,
+				Diffs:     assistantDiffs(&repository.Assistant{}, desired),
+				Assistant: desired,
+			})
+
+			continue
+		}
+
+		delete(managed, spec.Key)
+
+		change := response.AssistantChange{
+			Action:      response.AssistantChangeNoOp,
+			Key:         spec.Key,
+			AssistantID: current.ID,
+			Name:        spec.Name,
+			Diffs:       assistantDiffs(current, desired),
+			Assistant:   desired,
+		}
+
+		if len(change.Diffs) > 0 {
+			change.Action = response.AssistantChangeUpdate
+		}
+
+		plan.Changes = append(plan.Changes, change)
+	}
+
+	if prune {
+		keys := make([]string, 0, len(managed))
+		for key := range managed {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			plan.Changes = append(plan.Changes, response.AssistantChange{
+				Action:      response.AssistantChangeDelete,
+				Key:         key,
+				AssistantID: managed[key].ID,
+				Name:        managed[key].Name,
+			})
+		}
+	}
+
+	return plan, nil
+}
+
+// ApplySync is a function to apply the changes of the plan to OpenAI in order
+// It stops at the first failed change, the changes before it are already applied
+// Assistant ID of created assistant is set to the plan
+func (u *assistantUsecase) ApplySync(ctx context.Context, plan *response.AssistantSyncPlan) error {
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+
+		var err error
+
+		switch change.Action {
+		case response.AssistantChangeCreate:
+			var assistant *repository.Assistant
+
+			assistant, err = u.CreateAssistant(ctx, change.Assistant)
+			if err == nil {
+				change.AssistantID = assistant.ID
+			}
+		case response.AssistantChangeUpdate:
+			_, err = u.UpdateAssistant(ctx, change.AssistantID, change.Assistant)
+		case response.AssistantChangeDelete:
+			err = u.DeleteAssistant(ctx, change.AssistantID)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to %s assistant %s: %w", change.Action, change.Key, err)
+		}
+	}
+
+	log.Infow("Assistant Synced:",
+		"created", plan.Count(response.AssistantChangeCreate),
+		"updated", plan.Count(response.AssistantChangeUpdate),
+		"deleted", plan.Count(response.AssistantChangeDelete),
+	)
+
+	return nil
+}
+
+// listAllAssistants is a function to get all assistants from OpenAI API page by page
+func (u *assistantUsecase) listAllAssistants(ctx context.Context) ([]repository.Assistant, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(assistantListLimit))
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/assistants",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	return connector.ListAll[repository.Assistant](ctx, u.connector, httpRequestOption, query)
+}
+
+// validateManifest is a function to check every assistant of the manifest has unique key and valid fields
+func validateManifest(manifest *request.AssistantManifest) error {
+	keys := make(map[string]bool, len(manifest.Assistants))
+
+	for _, spec := range manifest.Assistants {
+		if spec.Key == "" {
+			return fmt.Errorf("%w: key is required", ErrAssistantManifest)
+		}
+
+		if keys[spec.Key] {
+			return fmt.Errorf("%w: duplicate key %s", ErrAssistantManifest, spec.Key)
+		}
+
+		keys[spec.Key] = true
+
+		if spec.Model == "" {
+			return fmt.Errorf("%w: %s: model is required", ErrAssistantManifest, spec.Key)
+		}
+
+		if err := validateAssistant(desiredAssistant(spec)); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrAssistantManifest, spec.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// desiredAssistant is a function to convert assistant spec into assistant request with every field set
+// The key is kept in AssistantManagedKey metadata to match the assistant on the next sync
+func desiredAssistant(spec request.AssistantSpec) *request.Assistant {
+	metadata := make(map[string]string, len(spec.Metadata)+1)
+	for key, value := range spec.Metadata {
+		metadata[key] = value
+	}
+
+	metadata[AssistantManagedKey] = spec.Key
+
+	tools := spec.Tools
+	if tools == nil {
+		tools = []repository.AssistantTool{}
+	}
+
+	fileIDs := slices.Clone(spec.FileIDs)
+	if fileIDs == nil {
+		fileIDs = []string{}
+	}
+
+	slices.Sort(fileIDs)
+
+	return &request.Assistant{
+		Model:        spec.Model,
+		Name:         &spec.Name,
+		Description:  &spec.Description,
+		Instructions: &spec.Instructions,
+		Tools:        &tools,
+		FileIDs:      &fileIDs,
+		Metadata:     metadata,
+	}
+}
+
+// assistantDiffs is a function to get managed fields of the assistant that are different from desired assistant
+// Order of file IDs, key order and whitespace of tool parameters are not a drift
+func assistantDiffs(current *repository.Assistant, desired *request.Assistant) []response.AssistantFieldDiff {
+	currentFileIDs := slices.Clone(current.FileIDs)
+	slices.Sort(currentFileIDs)
+
+	fields := []response.AssistantFieldDiff{
+		{Field: "name", Before: current.Name, After: *desired.Name},
+		{Field: "description", Before: current.Description, After: *desired.Description},
+		{Field: "model", Before: current.Model, After: desired.Model},
+		{Field: "instructions", Before: current.Instructions, After: *desired.Instructions},
+		{Field: "tools", Before: canonicalJSON(current.Tools), After: canonicalJSON(*desired.Tools)},
+		{Field: "file_ids", Before: canonicalJSON(currentFileIDs), After: canonicalJSON(*desired.FileIDs)},
+		{Field: "metadata", Before: canonicalJSON(current.Metadata), After: canonicalJSON(desired.Metadata)},
+	}
+
+	var diffs []response.AssistantFieldDiff
+
+	for _, field := range fields {
+		if field.Before != field.After {
+			diffs = append(diffs, field)
+		}
+	}
+
+	return diffs
+}
+
+// canonicalJSON is a function to encode value into JSON that is comparable, empty value is encoded as empty string
+func canonicalJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	// Round trip through generic value so raw JSON, e.g. tool parameters, has sorted keys and no whitespace
+	var generic any
+	if err := json.Unmarshal(data, &generic); err == nil {
+		if normalized, err := json.Marshal(generic); err == nil {
+			data = normalized
+		}
+	}
+
+	data = bytes.TrimSpace(data)
+
+	switch string(data) {
+	case "null", "[]", "{}":
+		return ""
+	default:
+		return string(data)
+	}
+}
@@ -0,0 +1,727 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestAssistantUsecase_CreateAssistant(t *testing.T) {
+	type args struct {
+		ctx       context.Context
+		assistant *request.Assistant
+	}
+
+	type test struct {
+		fields  assistantFields
+		args    args
+		want    *repository.Assistant
+		wantErr error
+	}
+
+	name := "Support"
+	instructions := "You are a support agent"
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Create Assistant, When repository executed successfully, Return created assistant": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+				assistant: &request.Assistant{
+					Model:        "gpt-4-turbo-preview",
+					Name:         &name,
+					Instructions: &instructions,
+					Tools:        &[]repository.AssistantTool{{Type: "retrieval"}},
+					FileIDs:      &[]string{"file-1"},
+					Metadata:     map[string]string{"team": "support"},
+				},
+			}
+
+			expected := &repository.Assistant{
+				ID:           "asst-1",
+				Object:       "assistant",
+				Name:         name,
+				Model:        "gpt-4-turbo-preview",
+				Instructions: instructions,
+				Tools:        []repository.AssistantTool{{Type: "retrieval"}},
+				FileIDs:      []string{"file-1"},
+				Metadata:     map[string]string{"team": "support"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+					assert.Equal(t, http.MethodPost, requestOption.Method)
+					assert.Equal(t, "/assistants", requestOption.URL)
+
+					body, err := io.ReadAll(requestOption.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, `{
+						"model": "gpt-4-turbo-preview",
+						"name": "Support",
+						"instructions": "You are a support agent",
+						"tools": [{"type": "retrieval"}],
+						"file_ids": ["file-1"],
+						"metadata": {"team": "support"}
+					}`, string(body))
+
+					*(result.(**repository.Assistant)) = expected
+
+					return nil
+				},
+			)
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				want:    expected,
+				wantErr: nil,
+			}
+		},
+		"Given request of Create Assistant without model, When it is validated, Return invalid error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: assistantFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx:       context.Background(),
+					assistant: &request.Assistant{Name: &name},
+				},
+				want:    nil,
+				wantErr: usecases.ErrAssistantInvalid,
+			}
+		},
+		"Given request of Create Assistant with function tool without name, When it is validated, Return invalid error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: assistantFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx: context.Background(),
+					assistant: &request.Assistant{
+						Model: "gpt-4-turbo-preview",
+						Tools: &[]repository.AssistantTool{{Type: "function"}},
+					},
+				},
+				want:    nil,
+				wantErr: usecases.ErrAssistantInvalid,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := assistantSut(tt.fields)
+
+			got, err := sut.CreateAssistant(tt.args.ctx, tt.args.assistant)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssistantUsecase_GetListAssistant(t *testing.T) {
+	type args struct {
+		ctx context.Context
+	}
+
+	type test struct {
+		fields  assistantFields
+		args    args
+		want    *response.List[repository.Assistant]
+		wantErr error
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/assistants?limit=100",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	nextRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/assistants?after=asst-1&limit=100",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get List Assistant, When assistants have more page, Return every assistant": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+			}
+
+			expected := []repository.Assistant{
+				{ID: "asst-1", Object: "assistant", Name: "Support", Model: "gpt-4-turbo-preview"},
+				{ID: "asst-2", Object: "assistant", Name: "Sales", Model: "gpt-4-turbo-preview"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{
+				Data:    expected[:1],
+				LastID:  "asst-1",
+				HasMore: true,
+			})
+			mockConnector.EXPECT().Send(args.ctx, nextRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{
+				Data:   expected[1:],
+				LastID: "asst-2",
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: &response.List[repository.Assistant]{
+					Data:   expected,
+					LastID: "asst-2",
+				},
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Get List Assistant, When there is no assistant, Return empty list": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: &response.List[repository.Assistant]{
+					Data: []repository.Assistant{},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Get List Assistant, When response is empty, Return list error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, gomock.Any()).Return(nil)
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				wantErr: connector.ErrList,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := assistantSut(tt.fields)
+
+			got, err := sut.GetListAssistant(tt.args.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssistantUsecase_UpdateAssistant(t *testing.T) {
+	type args struct {
+		ctx         context.Context
+		assistantID string
+		assistant   *request.Assistant
+	}
+
+	type test struct {
+		fields  assistantFields
+		args    args
+		want    *repository.Assistant
+		wantErr error
+	}
+
+	instructions := "You are a sales agent"
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given request of Update Assistant with some fields, When repository executed successfully, Return only the fields are sent": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:         context.Background(),
+				assistantID: "asst-1",
+				assistant: &request.Assistant{
+					Instructions: &instructions,
+					FileIDs:      &[]string{},
+				},
+			}
+
+			expected := &repository.Assistant{ID: "asst-1", Instructions: instructions}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+					assert.Equal(t, http.MethodPost, requestOption.Method)
+					assert.Equal(t, "/assistants/asst-1", requestOption.URL)
+
+					body, err := io.ReadAll(requestOption.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, `{"instructions": "You are a sales agent", "file_ids": []}`, string(body))
+
+					*(result.(**repository.Assistant)) = expected
+
+					return nil
+				},
+			)
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				want:    expected,
+				wantErr: nil,
+			}
+		},
+		"Given request of Update Assistant, When assistant is not found, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:         context.Background(),
+				assistantID: "asst-404",
+				assistant:   &request.Assistant{Instructions: &instructions},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(&connector.APIError{
+				HTTPStatus: http.StatusNotFound,
+				Message:    "No assistant found with id 'asst-404'.",
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: nil,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := assistantSut(tt.fields)
+
+			got, err := sut.UpdateAssistant(tt.args.ctx, tt.args.assistantID, tt.args.assistant)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.want == nil:
+				assert.True(t, connector.IsNotFound(err))
+			default:
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAssistantUsecase_DeleteAssistant(t *testing.T) {
+	type args struct {
+		ctx         context.Context
+		assistantID string
+	}
+
+	type test struct {
+		fields  assistantFields
+		args    args
+		wantErr error
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    "/assistants/asst-1",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Delete Assistant, When assistant is deleted, Return no error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:         context.Background(),
+				assistantID: "asst-1",
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID:      "asst-1",
+				Object:  "assistant.deleted",
+				Deleted: true,
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Delete Assistant, When assistant is not deleted, Return delete error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:         context.Background(),
+				assistantID: "asst-1",
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID: "asst-1",
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				wantErr: connector.ErrDelete,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := assistantSut(tt.fields)
+
+			err := sut.DeleteAssistant(tt.args.ctx, tt.args.assistantID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAssistantUsecase_PlanSync(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		manifest *request.AssistantManifest
+		prune    bool
+	}
+
+	type test struct {
+		fields  assistantFields
+		args    args
+		want    []response.AssistantChange
+		wantErr error
+	}
+
+	listRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/assistants?limit=100",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	nextListRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/assistants?after=asst-2&limit=100",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	manifest := &request.AssistantManifest{
+		Assistants: []request.AssistantSpec{
+			{
+				Key:          "support",
+				Name:         "Support",
+				Model:        "gpt-4-turbo-preview",
+				Instructions: "You are a support agent",
+				Tools: []repository.AssistantTool{{
+					Type: "function",
+					Function: &repository.AssistantFunction{
+						Name:       "get_time",
+						Parameters: json.RawMessage(`{ "type": "object", "properties": {} }`),
+					},
+				}},
+				FileIDs: []string{"file-2", "file-1"},
+			},
+			{
+				Key:          "sales",
+				Name:         "Sales",
+				Model:        "gpt-4-turbo-preview",
+				Instructions: "You are a sales agent",
+			},
+			{
+				Key:   "billing",
+				Name:  "Billing",
+				Model: "gpt-3.5-turbo",
+			},
+		},
+	}
+
+	assistants := []repository.Assistant{
+		{
+			ID:           "asst-1",
+			Name:         "Support Bot",
+			Model:        "gpt-4-turbo-preview",
+			Instructions: "You are a support agent",
+			Tools: []repository.AssistantTool{{
+				Type: "function",
+				Function: &repository.AssistantFunction{
+					Name:       "get_time",
+					Parameters: json.RawMessage(`{"properties":{},"type":"object"}`),
+				},
+			}},
+			FileIDs:  []string{"file-1", "file-2"},
+			Metadata: map[string]string{usecases.AssistantManagedKey: "support"},
+		},
+		{
+			ID:           "asst-2",
+			Name:         "Sales",
+			Model:        "gpt-4-turbo-preview",
+			Instructions: "You are a sales agent",
+			Metadata:     map[string]string{usecases.AssistantManagedKey: "sales"},
+		},
+		{
+			ID:       "asst-3",
+			Name:     "Legacy",
+			Model:    "gpt-3.5-turbo",
+			Metadata: map[string]string{usecases.AssistantManagedKey: "legacy"},
+		},
+		{
+			ID:    "asst-4",
+			Name:  "Created by hand",
+			Model: "gpt-3.5-turbo",
+		},
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given manifest with prune, When assistants are listed, Return create, update, no-op and delete changes": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				manifest: manifest,
+				prune:    true,
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, listRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{
+				Data:    assistants[:2],
+				LastID:  "asst-2",
+				HasMore: true,
+			})
+			mockConnector.EXPECT().Send(args.ctx, nextListRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{
+				Data:   assistants[2:],
+				LastID: "asst-4",
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: []response.AssistantChange{
+					{
+						Action:      response.AssistantChangeUpdate,
+						Key:         "support",
+						AssistantID: "asst-1",
+						Name:        "Support",
+						Diffs:       []response.AssistantFieldDiff{{Field: "name", Before: "Support Bot", After: "Support"}},
+					},
+					{
+						Action:      response.AssistantChangeNoOp,
+						Key:         "sales",
+						AssistantID: "asst-2",
+						Name:        "Sales",
+					},
+					{
+						Action: response.AssistantChangeCreate,
+						Key:    "billing",
+						Name:   "Billing",
+						Diffs: []response.AssistantFieldDiff{
+							{Field: "name", Before: "", After: "Billing"},
+							{Field: "model", Before: "", After: "gpt-3.5-turbo"},
+							{Field: "metadata", Before: "", After: `{"managed_key":"billing"}`},
+						},
+					},
+					{
+						Action:      response.AssistantChangeDelete,
+						Key:         "legacy",
+						AssistantID: "asst-3",
+						Name:        "Legacy",
+					},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given manifest without prune, When assistants are listed, Return no delete change": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+				manifest: &request.AssistantManifest{
+					Assistants: manifest.Assistants[1:2],
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, listRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIAssistant{
+				Data: assistants,
+			})
+
+			return test{
+				fields: assistantFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: []response.AssistantChange{
+					{
+						Action:      response.AssistantChangeNoOp,
+						Key:         "sales",
+						AssistantID: "asst-2",
+						Name:        "Sales",
+					},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given manifest with duplicate key, When it is validated, Return manifest error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: assistantFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx: context.Background(),
+					manifest: &request.AssistantManifest{
+						Assistants: []request.AssistantSpec{manifest.Assistants[1], manifest.Assistants[1]},
+					},
+				},
+				want:    nil,
+				wantErr: usecases.ErrAssistantManifest,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := assistantSut(tt.fields)
+
+			got, err := sut.PlanSync(tt.args.ctx, tt.args.manifest, tt.args.prune)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			for i := range got.Changes {
+				got.Changes[i].Assistant = nil
+			}
+
+			assert.Equal(t, tt.want, got.Changes)
+		})
+	}
+}
+
+func TestAssistantUsecase_ApplySync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	name := "Billing"
+	plan := &response.AssistantSyncPlan{
+		Changes: []response.AssistantChange{
+			{Action: response.AssistantChangeNoOp, Key: "sales", AssistantID: "asst-2"},
+			{Action: response.AssistantChangeCreate, Key: "billing", Assistant: &request.Assistant{Model: "gpt-3.5-turbo", Name: &name}},
+			{Action: response.AssistantChangeUpdate, Key: "support", AssistantID: "asst-1", Assistant: &request.Assistant{Name: &name}},
+			{Action: response.AssistantChangeDelete, Key: "legacy", AssistantID: "asst-3"},
+		},
+	}
+
+	mockConnector := connector.NewGoMockConnector(ctrl)
+	gomock.InOrder(
+		mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+				assert.Equal(t, "/assistants", requestOption.URL)
+				*(result.(**repository.Assistant)) = &repository.Assistant{ID: "asst-5"}
+
+				return nil
+			},
+		),
+		mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+				assert.Equal(t, "/assistants/asst-1", requestOption.URL)
+				*(result.(**repository.Assistant)) = &repository.Assistant{ID: "asst-1"}
+
+				return nil
+			},
+		),
+		mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).Return(&connector.APIError{
+			HTTPStatus: http.StatusInternalServerError,
+			Message:    "boom",
+		}),
+	)
+
+	sut := assistantSut(assistantFields{connector: mockConnector})
+
+	err := sut.ApplySync(ctx, plan)
+
+	assert.True(t, connector.IsServerError(err))
+	assert.ErrorContains(t, err, "failed to delete assistant legacy")
+	assert.Equal(t, "asst-5", plan.Changes[1].AssistantID)
+}
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+type AssistantUsecase interface {
+	CreateAssistant(ctx context.Context, assistant *request.Assistant) (*repository.Assistant, error)
+	GetListAssistant(ctx context.Context) (*response.List[repository.Assistant], error)
+	GetAssistant(ctx context.Context, assistantID string) (*repository.Assistant, error)
+	UpdateAssistant(ctx context.Context, assistantID string, assistant *request.Assistant) (*repository.Assistant, error)
+	DeleteAssistant(ctx context.Context, assistantID string) error
+	PlanSync(ctx context.Context, manifest *request.AssistantManifest, prune bool) (*response.AssistantSyncPlan, error)
+	ApplySync(ctx context.Context, plan *response.AssistantSyncPlan) error
+}
+
+func NewAssistantUsecase(connector connector.Connector) AssistantUsecase {
+	return &assistantUsecase{
+		connector: connector,
+	}
+}
+
+type assistantUsecase struct {
+	connector connector.Connector
+}
+
+const (
+	// Assistant limits of OpenAI API
+	maxAssistantNameLength         = 256
+	maxAssistantDescriptionLength  = 512
+	maxAssistantInstructionsLength = 32768
+	maxAssistantTools              = 128
+	maxAssistantFiles              = 20
+	// assistantListLimit is the page size to list assistants, it is the maximum allowed by OpenAI
+	assistantListLimit = 100
+	// AssistantManagedKey is the metadata key to match assistant with its spec in manifest
+	AssistantManagedKey = "managed_key"
+)
+
+var (
+	ErrAssistantInvalid  = errors.New("assistant is invalid")
+	ErrAssistantManifest = errors.New("assistant manifest is invalid")
+)
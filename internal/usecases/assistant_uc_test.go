@@ -0,0 +1,16 @@
+package usecases_test
+
+import (
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+type assistantFields struct {
+	connector connector.Connector
+}
+
+func assistantSut(f assistantFields) usecases.AssistantUsecase {
+	return usecases.NewAssistantUsecase(
+		f.connector,
+	)
+}
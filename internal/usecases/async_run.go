@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yonisaka/assistant/internal/entities/response"
+)
+
+const (
+	// maxAsyncRuns is the maximum number of runs driven in background at the same time
+	maxAsyncRuns = 32
+	// asyncRunTTL is how long a finished run can be fetched
+	asyncRunTTL = time.Hour
+)
+
+var (
+	ErrRunNotFound = errors.New("run not found")
+	ErrTooManyRuns = errors.New("too many runs in background")
+)
+
+// asyncRun is a struct of run driven in background
+type asyncRun struct {
+	ownerID string
+	run     response.Run
+}
+
+// asyncRunTracker is a struct to keep state of background runs in memory
+// The state is local to the process, so the client must fetch the run from the same instance
+type asyncRunTracker struct {
+	mu        sync.RWMutex
+	runs      map[string]*asyncRun
+	semaphore chan struct{}
+}
+
+func newAsyncRunTracker() *asyncRunTracker {
+	return &asyncRunTracker{
+		runs:      make(map[string]*asyncRun),
+		semaphore: make(chan struct{}, maxAsyncRuns),
+	}
+}
+
+// acquire is a function to take a slot of background run without waiting, it returns false when every slot is taken
+func (t *asyncRunTracker) acquire() bool {
+	select {
+	case t.semaphore <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release is a function to give back the slot taken by acquire
+func (t *asyncRunTracker) release() {
+	<-t.semaphore
+}
+
+// add is a function to track new run, finished runs older than TTL are removed
+func (t *asyncRunTracker) add(ownerID string, run response.Run) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	expired := time.Now().Add(-asyncRunTTL).Unix()
+	for id, tracked := range t.runs {
+		if tracked.run.FinishedAt > 0 && tracked.run.FinishedAt < expired {
+			delete(t.runs, id)
+		}
+	}
+
+	t.runs[run.ID] = &asyncRun{
+		ownerID: ownerID,
+		run:     run,
+	}
+}
+
+// update is a function to change tracked run
+func (t *asyncRunTracker) update(id string, fn func(run *response.Run)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tracked, ok := t.runs[id]; ok {
+		fn(&tracked.run)
+	}
+}
+
+// get is a function to get copy of tracked run of the owner
+func (t *asyncRunTracker) get(id, ownerID string) (*response.Run, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	tracked, ok := t.runs[id]
+	if !ok || tracked.ownerID != ownerID {
+		return nil, ErrRunNotFound
+	}
+
+	run := tracked.run
+
+	return &run, nil
+}
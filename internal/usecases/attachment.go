@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+)
+
+const (
+	// maxMessageFiles is the maximum files attached to a message allowed by OpenAI
+	maxMessageFiles = 10
+	// promptFilePurpose is the purpose of file uploaded with prompt, it is read by the assistant
+	promptFilePurpose = "assistants"
+)
+
+// MaxPromptFileSize is the maximum size in bytes of file uploaded inline with prompt, i.e. 10 MiB after it is decoded
+// It is sent base64 encoded in the prompt body, so the body limit of prompt endpoints must fit its encoded size
+const MaxPromptFileSize = 10 << 20
+
+var (
+	ErrAttachmentInvalid    = errors.New("invalid attachment")
+	ErrAttachmentNotAllowed = errors.New("attachment is not allowed")
+)
+
+// validateAttachments is a function to check count and inline file of the prompt before any request is sent
+func validateAttachments(prompt *request.Prompt) error {
+	count := len(prompt.FileIDs)
+	if prompt.File != nil {
+		count++
+	}
+
+	if count > maxMessageFiles {
+		return fmt.Errorf("%w: more than %d files", ErrAttachmentInvalid, maxMessageFiles)
+	}
+
+	for i, fileID := range prompt.FileIDs {
+		if fileID == "" {
+			return fmt.Errorf("%w: file_id is empty", ErrAttachmentInvalid)
+		}
+
+		if slices.Contains(prompt.FileIDs[:i], fileID) {
+			return fmt.Errorf("%w: file_id %s is attached twice", ErrAttachmentInvalid, fileID)
+		}
+	}
+
+	if prompt.File == nil {
+		return nil
+	}
+
+	switch {
+	case prompt.File.Filename == "":
+		return fmt.Errorf("%w: file name is required", ErrAttachmentInvalid)
+	case len(prompt.File.Data) == 0:
+		return fmt.Errorf("%w: file is empty", ErrAttachmentInvalid)
+	case len(prompt.File.Data) > MaxPromptFileSize:
+		return fmt.Errorf("%w: file is larger than %d bytes", ErrAttachmentInvalid, MaxPromptFileSize)
+	}
+
+	return nil
+}
+
+// attachFiles is a function to check the prompt can use its files and upload its inline file
+// A file can be attached when it is uploaded earlier in the conversation or allowed by the run policy,
+// the file that is not allowed is rejected before it is read, so its existence is not leaked
+func (u *promptUsecase) attachFiles(
+	ctx context.Context,
+	prompt *request.Prompt,
+	conversation *repository.Conversation,
+) ([]response.Attachment, error) {
+	var attachments []response.Attachment
+
+	for _, fileID := range prompt.FileIDs {
+		if !slices.Contains(conversation.FileIDs, fileID) && !u.runPolicy.AllowsFile(prompt, fileID) {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentNotAllowed, fileID)
+		}
+	}
+
+	for _, fileID := range prompt.FileIDs {
+		file, err := u.fileUsecase.GetFile(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, response.Attachment{
+			FileID:   file.ID,
+			Filename: file.Filename,
+			Bytes:    file.Bytes,
+		})
+	}
+
+	if prompt.File == nil {
+		return attachments, nil
+	}
+
+	file, err := u.fileUsecase.UploadFile(ctx, &request.UploadFile{
+		Purpose:     promptFilePurpose,
+		Filename:    prompt.File.Filename,
+		ContentType: prompt.File.ContentType,
+		Size:        int64(len(prompt.File.Data)),
+		Reader:      bytes.NewReader(prompt.File.Data),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Prompt File Uploaded:", "conversation_id", conversation.ID, "file_id", file.ID)
+
+	// The file belongs to the conversation from now on, even when the run fails
+	conversation.FileIDs = append(conversation.FileIDs, file.ID)
+	u.touchConversation(ctx, conversation, file.ID)
+
+	return append(attachments, response.Attachment{
+		FileID:   file.ID,
+		Filename: file.Filename,
+		Bytes:    file.Bytes,
+		Uploaded: true,
+	}), nil
+}
+
+// attachmentFileIDs is a function to get file ids of attachments to be sent with the message
+func attachmentFileIDs(attachments []response.Attachment) []string {
+	var fileIDs []string
+
+	for _, attachment := range attachments {
+		fileIDs = append(fileIDs, attachment.FileID)
+	}
+
+	return fileIDs
+}
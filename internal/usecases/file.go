@@ -2,26 +2,196 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 
+	"github.com/gofiber/fiber/v2/log"
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 )
 
-// GetListFile is a function to get list file from OpenAI API
-func (u *fileUsecase) GetListFile(ctx context.Context) (*[]repository.File, error) {
+// GetListFile is a function to get a page of list file from OpenAI API
+// All option pages through every file, so the result has no more page
+func (u *fileUsecase) GetListFile(ctx context.Context, option *request.ListFile) (*response.List[repository.File], error) {
+	if option.Before != "" {
+		return nil, fmt.Errorf("%w: before is not supported to list files", ErrListOptionInvalid)
+	}
+
+	query, err := listQuery(option.ListOption, maxFileListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if option.Purpose != "" {
+		query.Set("purpose", option.Purpose)
+	}
+
 	// Set HTTP Request Parameter
 	httpRequestOption := &connector.RequestOption{
 		Method: http.MethodGet,
 		URL:    "/files",
 	}
 
+	if option.All {
+		return u.listAllFiles(ctx, httpRequestOption, query)
+	}
+
+	if len(query) > 0 {
+		httpRequestOption.URL += "?" + query.Encode()
+	}
+
 	var response *connector.OpenAIFile
 	// Do HTTP Request
-	err := u.connector.Send(ctx, httpRequestOption, &response)
+	err = u.connector.Send(ctx, httpRequestOption, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		return nil, connector.ErrList
+	}
+
+	return fileList(response.Data, response.HasMore, response.LastID), nil
+}
+
+// listAllFiles is a function to get every file from OpenAI API page by page
+func (u *fileUsecase) listAllFiles(ctx context.Context, httpRequestOption *connector.RequestOption, query url.Values) (*response.List[repository.File], error) {
+	files, err := connector.ListAll[repository.File](ctx, u.connector, httpRequestOption, query)
 	if err != nil {
 		return nil, err
 	}
 
-	return &response.Data, nil
+	log.Infow("File Listed:", "files", len(files))
+
+	return fileList(files, false, ""), nil
+}
+
+// fileList is a function to create list file sent to client
+// LastID falls back to the last file because OpenAI does not always send the cursor of files
+func fileList(files []repository.File, hasMore bool, lastID string) *response.List[repository.File] {
+	if files == nil {
+		files = make([]repository.File, 0)
+	}
+
+	if lastID == "" && len(files) > 0 {
+		lastID = files[len(files)-1].ID
+	}
+
+	return &response.List[repository.File]{
+		Data:    files,
+		HasMore: hasMore,
+		LastID:  lastID,
+	}
+}
+
+// UploadFile is a function to upload file to OpenAI API
+// The file is streamed as multipart body, so its Reader is read only once and never buffered
+func (u *fileUsecase) UploadFile(ctx context.Context, upload *request.UploadFile) (*repository.File, error) {
+	switch {
+	case upload.Purpose == "":
+		return nil, fmt.Errorf("%w: purpose is required", ErrFileInvalid)
+	case upload.Filename == "" || upload.Reader == nil:
+		return nil, fmt.Errorf("%w: file is required", ErrFileInvalid)
+	}
+
+	// Set HTTP Request Parameter
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    "/files",
+		Multipart: []connector.MultipartField{
+			{
+				Name:  "purpose",
+				Value: upload.Purpose,
+			},
+			{
+				Name:        "file",
+				Filename:    upload.Filename,
+				ContentType: upload.ContentType,
+				Reader:      upload.Reader,
+				Size:        upload.Size,
+			},
+		},
+	}
+
+	var result *repository.File
+	// Do HTTP Request
+	err := u.connector.Send(ctx, httpRequestOption, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil || result.ID == "" {
+		return nil, connector.ErrFile
+	}
+
+	return result, nil
+}
+
+// GetFile is a function to get file from OpenAI API
+func (u *fileUsecase) GetFile(ctx context.Context, fileID string) (*repository.File, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    fmt.Sprintf("/files/%s", fileID),
+	}
+
+	var result *repository.File
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil || result.ID == "" {
+		return nil, connector.ErrFile
+	}
+
+	return result, nil
+}
+
+// DeleteFile is a function to delete file from OpenAI API
+func (u *fileUsecase) DeleteFile(ctx context.Context, fileID string) error {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    fmt.Sprintf("/files/%s", fileID),
+	}
+
+	var result *connector.OpenAIDeleted
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return err
+	}
+
+	if result == nil || !result.Deleted {
+		return connector.ErrDelete
+	}
+
+	log.Infow("File Deleted:", "id", fileID)
+
+	return nil
+}
+
+// GetFileContent is a function to get raw content of file from OpenAI API
+// The content is not read here, the caller streams it and must close the Body
+func (u *fileUsecase) GetFileContent(ctx context.Context, fileID string) (*response.FileContent, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    fmt.Sprintf("/files/%s/content", fileID),
+	}
+
+	result, err := u.connector.SendRaw(ctx, httpRequestOption)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := result.Header.Get(connector.HeaderContentType)
+	if contentType == "" {
+		contentType = defaultFileContentType
+	}
+
+	return &response.FileContent{
+		Body:               result.Body,
+		ContentType:        contentType,
+		ContentDisposition: result.Header.Get(connector.HeaderContentDisposition),
+		ContentLength:      result.ContentLength,
+	}, nil
 }
@@ -0,0 +1,355 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+)
+
+// SyncFiles is a function to sync local folder into files of an assistant with several steps below:
+// 1. Hash every local file and get the synced files of the assistant, they are matched by the path in their name
+// 2. Plan to upload new file, replace changed file and delete file that is removed from the folder
+// 3. Upload the files, update file_ids of the assistant, then delete the replaced and removed files
+// Files of the assistant that are not uploaded by sync are kept as they are
+func (u *fileUsecase) SyncFiles(ctx context.Context, sync *request.FileSync) (*response.FileSyncReport, error) {
+	switch {
+	case sync.AssistantID == "":
+		return nil, fmt.Errorf("%w: assistant_id is required", ErrFileSyncInvalid)
+	case sync.Dir == "":
+		return nil, fmt.Errorf("%w: dir is required", ErrFileSyncInvalid)
+	}
+
+	fsys := os.DirFS(sync.Dir)
+
+	locals, err := scanLocalFiles(fsys, sync.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	assistant, err := u.assistantUsecase.GetAssistant(ctx, sync.AssistantID)
+	if err != nil {
+		return nil, err
+	}
+
+	remotes, unmanaged, err := u.getSyncedFiles(ctx, assistant.FileIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unmanaged)+len(locals) > maxAssistantFiles {
+		return nil, fmt.Errorf("%w: %d files and %d files that are not synced are more than %d files of assistant",
+			ErrFileSyncInvalid, len(locals), len(unmanaged), maxAssistantFiles)
+	}
+
+	report := planFileSync(locals, remotes)
+	report.AssistantID = sync.AssistantID
+	report.DryRun = sync.DryRun
+
+	if sync.DryRun {
+		return report, nil
+	}
+
+	if len(report.Changes) == report.Count(response.FileSyncUnchanged) {
+		report.FileIDs = assistant.FileIDs
+		return report, nil
+	}
+
+	if err := u.uploadSyncFiles(ctx, fsys, report); err != nil {
+		u.deleteUploadedFiles(ctx, report)
+		return nil, err
+	}
+
+	fileIDs := slices.Clone(unmanaged)
+	for _, change := range report.Changes {
+		if change.Action != response.FileSyncDelete {
+			fileIDs = append(fileIDs, change.FileID)
+		}
+	}
+
+	if _, err := u.assistantUsecase.UpdateAssistant(ctx, sync.AssistantID, &request.Assistant{FileIDs: &fileIDs}); err != nil {
+		u.deleteUploadedFiles(ctx, report)
+		return nil, err
+	}
+
+	u.deleteSyncFiles(ctx, report)
+	report.FileIDs = fileIDs
+
+	log.Infow("File Synced:", "assistant_id", sync.AssistantID,
+		"uploaded", report.Count(response.FileSyncUpload),
+		"replaced", report.Count(response.FileSyncReplace),
+		"deleted", report.Count(response.FileSyncDelete),
+	)
+
+	return report, nil
+}
+
+// localFile is a struct of file in the synced folder, path is slash separated and relative to the folder
+type localFile struct {
+	path   string
+	sha256 string
+	size   int64
+}
+
+// syncedFile is a struct of file of the assistant that is uploaded by sync, its path and hash are parsed from its name
+type syncedFile struct {
+	id   string
+	path string
+	hash string
+}
+
+// scanLocalFiles is a function to hash every regular file of the folder in lexical order
+// Hidden files and folders, e.g. .git, are skipped, extensions filter the files when they are set
+func scanLocalFiles(fsys fs.FS, extensions []string) ([]localFile, error) {
+	var files []localFile
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name != "." && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		if len(extensions) > 0 && !slices.Contains(extensions, strings.ToLower(path.Ext(name))) {
+			return nil
+		}
+
+		file, err := hashLocalFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, *file)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// hashLocalFile is a function to read the file once to get its SHA-256 and size
+func hashLocalFile(fsys fs.FS, name string) (*localFile, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localFile{
+		path:   name,
+		sha256: hex.EncodeToString(hash.Sum(nil)),
+		size:   size,
+	}, nil
+}
+
+// getSyncedFiles is a function to get files of the assistant and split the synced files from the others
+func (u *fileUsecase) getSyncedFiles(ctx context.Context, fileIDs []string) ([]syncedFile, []string, error) {
+	var (
+		synced    []syncedFile
+		unmanaged []string
+	)
+
+	for _, fileID := range fileIDs {
+		file, err := u.GetFile(ctx, fileID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		filePath, hash, ok := parseSyncedFilename(file.Filename)
+		if !ok {
+			unmanaged = append(unmanaged, fileID)
+			continue
+		}
+
+		synced = append(synced, syncedFile{
+			id:   fileID,
+			path: filePath,
+			hash: hash,
+		})
+	}
+
+	return synced, unmanaged, nil
+}
+
+// planFileSync is a function to compare local files with synced files by path and hash
+// A path that is synced more than once keeps only its first file, the others are deleted
+func planFileSync(locals []localFile, synced []syncedFile) *response.FileSyncReport {
+	byPath := make(map[string]syncedFile, len(synced))
+	for _, file := range synced {
+		if _, ok := byPath[file.path]; !ok {
+			byPath[file.path] = file
+		}
+	}
+
+	report := &response.FileSyncReport{
+		Changes: make([]response.FileSyncChange, 0, len(locals)),
+	}
+
+	matched := make(map[string]bool, len(locals))
+
+	for _, local := range locals {
+		change := response.FileSyncChange{
+			Action: response.FileSyncUpload,
+			Path:   local.path,
+			SHA256: local.sha256,
+			Bytes:  local.size,
+		}
+
+		if remote, ok := byPath[local.path]; ok {
+			matched[remote.id] = true
+
+			if remote.hash == local.sha256[:fileSyncHashLength] {
+				change.Action = response.FileSyncUnchanged
+				change.FileID = remote.id
+			} else {
+				change.Action = response.FileSyncReplace
+				change.PreviousFileID = remote.id
+			}
+		}
+
+		report.Changes = append(report.Changes, change)
+	}
+
+	for _, remote := range synced {
+		if matched[remote.id] {
+			continue
+		}
+
+		report.Changes = append(report.Changes, response.FileSyncChange{
+			Action:         response.FileSyncDelete,
+			Path:           remote.path,
+			PreviousFileID: remote.id,
+		})
+	}
+
+	return report
+}
+
+// uploadSyncFiles is a function to upload new and changed files, the name of uploaded file keeps its path and hash
+func (u *fileUsecase) uploadSyncFiles(ctx context.Context, fsys fs.FS, report *response.FileSyncReport) error {
+	for i := range report.Changes {
+		change := &report.Changes[i]
+		if change.Action != response.FileSyncUpload && change.Action != response.FileSyncReplace {
+			continue
+		}
+
+		file, err := fsys.Open(change.Path)
+		if err != nil {
+			return err
+		}
+
+		result, err := u.UploadFile(ctx, &request.UploadFile{
+			Purpose:     fileSyncPurpose,
+			Filename:    syncedFilename(change.Path, change.SHA256[:fileSyncHashLength]),
+			ContentType: mime.TypeByExtension(path.Ext(change.Path)),
+			Size:        change.Bytes,
+			Reader:      file,
+		})
+		file.Close()
+
+		if err != nil {
+			return fmt.Errorf("upload %s: %w", change.Path, err)
+		}
+
+		change.FileID = result.ID
+	}
+
+	return nil
+}
+
+// deleteSyncFiles is a function to delete replaced and removed files after they are detached from the assistant
+// Failure is only reported on the change because the assistant is already synced
+func (u *fileUsecase) deleteSyncFiles(ctx context.Context, report *response.FileSyncReport) {
+	for i := range report.Changes {
+		change := &report.Changes[i]
+		if change.PreviousFileID == "" {
+			continue
+		}
+
+		if err := u.DeleteFile(ctx, change.PreviousFileID); err != nil {
+			log.Warnw("Delete Synced File Failed:", "file_id", change.PreviousFileID, "error", err)
+			change.Error = err.Error()
+		}
+	}
+}
+
+// deleteUploadedFiles is a function to delete files uploaded by a sync that fails, so they are not left unused
+func (u *fileUsecase) deleteUploadedFiles(ctx context.Context, report *response.FileSyncReport) {
+	for _, change := range report.Changes {
+		if change.Action == response.FileSyncUnchanged || change.FileID == "" {
+			continue
+		}
+
+		if err := u.DeleteFile(ctx, change.FileID); err != nil {
+			log.Warnw("Delete Uploaded File Failed:", "file_id", change.FileID, "error", err)
+		}
+	}
+}
+
+// syncedFilename is a function to put the hash of content before the extension, e.g. docs/guide.3f2a9c1d0e4b5a6f.md
+// The extension is kept last so OpenAI still knows the type of file
+func syncedFilename(filePath, hash string) string {
+	extension := path.Ext(filePath)
+
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(filePath, extension), hash, extension)
+}
+
+// parseSyncedFilename is a function to get the path and the hash of content from the name of synced file
+func parseSyncedFilename(filename string) (string, string, bool) {
+	extension := path.Ext(filename)
+	stem := strings.TrimSuffix(filename, extension)
+
+	// File without extension has the hash as its extension
+	if hash := strings.TrimPrefix(extension, "."); isSyncHash(hash) {
+		return stem, hash, true
+	}
+
+	index := strings.LastIndex(stem, ".")
+	if index < 0 || !isSyncHash(stem[index+1:]) {
+		return "", "", false
+	}
+
+	return stem[:index] + extension, stem[index+1:], true
+}
+
+// isSyncHash is a function to check the value is lowercase hex hash kept in the name of synced file
+func isSyncHash(value string) bool {
+	if len(value) != fileSyncHashLength {
+		return false
+	}
+
+	_, err := hex.DecodeString(value)
+
+	return err == nil && strings.ToLower(value) == value
+}
@@ -2,23 +2,34 @@ package usecases_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
 	"go.uber.org/mock/gomock"
+	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestFileUsecase_GetListFile(t *testing.T) {
 	type args struct {
-		ctx context.Context
+		ctx    context.Context
+		option *request.ListFile
 	}
 
 	type test struct {
 		fields  fileFields
 		args    args
-		want    *[]repository.File
+		want    *response.List[repository.File]
 		wantErr error
 	}
 
@@ -27,7 +38,8 @@ func TestFileUsecase_GetListFile(t *testing.T) {
 			ctx := context.Background()
 
 			args := args{
-				ctx: ctx,
+				ctx:    ctx,
+				option: &request.ListFile{},
 			}
 
 			mockConnector := connector.NewGoMockConnector(ctrl)
@@ -57,15 +69,693 @@ func TestFileUsecase_GetListFile(t *testing.T) {
 				fields: fileFields{
 					connector: mockConnector,
 				},
-				args:    args,
-				want:    &expected,
+				args: args,
+				want: &response.List[repository.File]{
+					Data:   expected,
+					LastID: "file-1",
+				},
 				wantErr: nil,
 			}
 		},
+		"Given request of Get List File with cursor and purpose, When repository has more files, Return page with cursor": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				option: &request.ListFile{
+					ListOption: request.ListOption{Limit: 2, After: "file-1", Order: "asc"},
+					Purpose:    "assistants",
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			httpRequestOption := &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files?after=file-1&limit=2&order=asc&purpose=assistants",
+			}
+
+			expected := []repository.File{{ID: "file-2"}, {ID: "file-3"}}
+
+			var result *connector.OpenAIFile
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, &result).Return(nil).SetArg(2, &connector.OpenAIFile{
+				Data:    expected,
+				LastID:  "file-3",
+				HasMore: true,
+			})
+
+			return test{
+				fields: fileFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: &response.List[repository.File]{
+					Data:    expected,
+					HasMore: true,
+					LastID:  "file-3",
+				},
+			}
+		},
+		"Given request of Get List File with all option, When repository has several pages, Return every file": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				option: &request.ListFile{
+					ListOption: request.ListOption{Limit: 2},
+					All:        true,
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			var result *connector.OpenAIFile
+			gomock.InOrder(
+				mockConnector.EXPECT().Send(args.ctx, &connector.RequestOption{
+					Method: http.MethodGet,
+					URL:    "/files?limit=2",
+				}, &result).Return(nil).SetArg(2, &connector.OpenAIFile{
+					Data:    []repository.File{{ID: "file-1"}, {ID: "file-2"}},
+					LastID:  "file-2",
+					HasMore: true,
+				}),
+				mockConnector.EXPECT().Send(args.ctx, &connector.RequestOption{
+					Method: http.MethodGet,
+					URL:    "/files?after=file-2&limit=2",
+				}, &result).Return(nil).SetArg(2, &connector.OpenAIFile{
+					Data:   []repository.File{{ID: "file-3"}},
+					LastID: "file-3",
+				}),
+			)
+
+			return test{
+				fields: fileFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: &response.List[repository.File]{
+					Data:   []repository.File{{ID: "file-1"}, {ID: "file-2"}, {ID: "file-3"}},
+					LastID: "file-3",
+				},
+			}
+		},
+		"Given request of Get List File with invalid limit, When Get List File, Return invalid list option error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: fileFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx:    context.Background(),
+					option: &request.ListFile{ListOption: request.ListOption{Limit: 10001}},
+				},
+				wantErr: usecases.ErrListOptionInvalid,
+			}
+		},
+		"Given request of Get List File with before cursor, When Get List File, Return invalid list option error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: fileFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx:    context.Background(),
+					option: &request.ListFile{ListOption: request.ListOption{Before: "file-1"}},
+				},
+				wantErr: usecases.ErrListOptionInvalid,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := fileSut(tt.fields)
+
+			got, err := sut.GetListFile(tt.args.ctx, tt.args.option)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileUsecase_UploadFile(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		upload *request.UploadFile
+	}
+
+	type test struct {
+		fields  fileFields
+		args    args
+		want    *repository.File
+		wantErr error
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Upload File, When repository executed successfully, Return uploaded file": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				upload: &request.UploadFile{
+					Purpose:     "assistants",
+					Filename:    "notes.txt",
+					ContentType: "text/plain",
+					Size:        11,
+					Reader:      strings.NewReader("hello world"),
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			httpRequestOption := &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/files",
+				Multipart: []connector.MultipartField{
+					{Name: "purpose", Value: "assistants"},
+					{Name: "file", Filename: "notes.txt", ContentType: "text/plain", Reader: args.upload.Reader, Size: 11},
+				},
+			}
+
+			expected := &repository.File{
+				ID:       "file-1",
+				Object:   "file",
+				Purpose:  "assistants",
+				Filename: "notes.txt",
+				Bytes:    11,
+			}
+
+			var result *repository.File
+			mockConnector.EXPECT().Send(args.ctx, httpRequestOption, &result).Return(nil).SetArg(2, expected)
+
+			return test{
+				fields: fileFields{
+					connector: mockConnector,
+				},
+				args: args,
+				want: expected,
+			}
+		},
+		"Given request without purpose, When Upload File, Return invalid file error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: fileFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx:    context.Background(),
+					upload: &request.UploadFile{Filename: "notes.txt", Reader: strings.NewReader("hello world")},
+				},
+				wantErr: usecases.ErrFileInvalid,
+			}
+		},
+		"Given request without file, When Upload File, Return invalid file error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: fileFields{
+					connector: connector.NewGoMockConnector(ctrl),
+				},
+				args: args{
+					ctx:    context.Background(),
+					upload: &request.UploadFile{Purpose: "assistants"},
+				},
+				wantErr: usecases.ErrFileInvalid,
+			}
+		},
+		"Given valid request of Upload File, When repository returns error, Return the error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				upload: &request.UploadFile{
+					Purpose:  "assistants",
+					Filename: "notes.txt",
+					Reader:   strings.NewReader("hello world"),
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			errUpload := errors.New("upload failed")
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(errUpload)
+
+			return test{
+				fields: fileFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				wantErr: errUpload,
+			}
+		},
+		"Given valid request of Upload File, When response has no file ID, Return file error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				upload: &request.UploadFile{
+					Purpose:  "assistants",
+					Filename: "notes.txt",
+					Reader:   strings.NewReader("hello world"),
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.File{})
+
+			return test{
+				fields: fileFields{
+					connector: mockConnector,
+				},
+				args:    args,
+				wantErr: connector.ErrFile,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := fileSut(tt.fields)
+
+			got, err := sut.UploadFile(tt.args.ctx, tt.args.upload)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileUsecase_GetFile(t *testing.T) {
+	type test struct {
+		fields  fileFields
+		want    *repository.File
+		wantErr error
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get File, When repository executed successfully, Return the file": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			httpRequestOption := &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files/file-1",
+			}
+
+			expected := &repository.File{ID: "file-1", Object: "file", Filename: "notes.txt"}
+
+			var result *repository.File
+			mockConnector.EXPECT().Send(gomock.Any(), httpRequestOption, &result).Return(nil).SetArg(2, expected)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				want:   expected,
+			}
+		},
+		"Given valid request of Get File, When repository returns error, Return the error": func(t *testing.T, ctrl *gomock.Controller) test {
+			errNotFound := errors.New("not found")
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(errNotFound)
+
+			return test{
+				fields:  fileFields{connector: mockConnector},
+				wantErr: errNotFound,
+			}
+		},
+		"Given valid request of Get File, When response is empty, Return file error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			return test{
+				fields:  fileFields{connector: mockConnector},
+				wantErr: connector.ErrFile,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := fileSut(tt.fields)
+
+			got, err := sut.GetFile(context.Background(), "file-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileUsecase_DeleteFile(t *testing.T) {
+	type test struct {
+		fields  fileFields
+		wantErr error
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    "/files/file-1",
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Delete File, When file is deleted, Return no error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			var result *connector.OpenAIDeleted
+			mockConnector.EXPECT().Send(gomock.Any(), httpRequestOption, &result).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID:      "file-1",
+				Object:  "file",
+				Deleted: true,
+			})
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+			}
+		},
+		"Given valid request of Delete File, When file is not deleted, Return delete error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			var result *connector.OpenAIDeleted
+			mockConnector.EXPECT().Send(gomock.Any(), httpRequestOption, &result).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID: "file-1",
+			})
+
+			return test{
+				fields:  fileFields{connector: mockConnector},
+				wantErr: connector.ErrDelete,
+			}
+		},
 	}
 
 	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
 
+			sut := fileSut(tt.fields)
+
+			err := sut.DeleteFile(context.Background(), "file-1")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFileUsecase_GetFileContent(t *testing.T) {
+	type test struct {
+		fields  fileFields
+		want    *response.FileContent
+		wantErr bool
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/files/file-1/content",
+	}
+
+	body := io.NopCloser(strings.NewReader("hello world"))
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get File Content, When repository executed successfully, Return body with its headers": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().SendRaw(gomock.Any(), httpRequestOption).Return(&connector.RawResponse{
+				Body: body,
+				Header: http.Header{
+					"Content-Type":        []string{"text/plain"},
+					"Content-Disposition": []string{`attachment; filename="notes.txt"`},
+				},
+				ContentLength: 11,
+			}, nil)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				want: &response.FileContent{
+					Body:               body,
+					ContentType:        "text/plain",
+					ContentDisposition: `attachment; filename="notes.txt"`,
+					ContentLength:      11,
+				},
+			}
+		},
+		"Given valid request of Get File Content, When content type is not sent, Return binary content type": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().SendRaw(gomock.Any(), httpRequestOption).Return(&connector.RawResponse{
+				Body:          body,
+				Header:        http.Header{},
+				ContentLength: -1,
+			}, nil)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				want: &response.FileContent{
+					Body:          body,
+					ContentType:   "application/octet-stream",
+					ContentLength: -1,
+				},
+			}
+		},
+		"Given valid request of Get File Content, When repository returns error, Return the error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().SendRaw(gomock.Any(), httpRequestOption).Return(nil, errors.New("not found"))
+
+			return test{
+				fields:  fileFields{connector: mockConnector},
+				wantErr: true,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := fileSut(tt.fields)
+
+			got, err := sut.GetFileContent(context.Background(), "file-1")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileUsecase_SyncFiles(t *testing.T) {
+	type test struct {
+		fields  fileFields
+		sync    *request.FileSync
+		want    *response.FileSyncReport
+		wantErr error
+	}
+
+	shortHash := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])[:16]
+	}
+
+	fullHash := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return hex.EncodeToString(sum[:])
+	}
+
+	// newFolder is a function to create folder with a changed, an unchanged, a hidden and a filtered file
+	newFolder := func(t *testing.T) string {
+		dir := t.TempDir()
+
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0o755))
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "a.md"), []byte("new a"), 0o600))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.md"), []byte("b"), 0o600))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "config.md"), []byte("git"), 0o600))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "tool.exe"), []byte("exe"), 0o600))
+
+		return dir
+	}
+
+	// expectRemote is a function to expect the assistant and its files, one of each kind of synced file and a manual file
+	expectRemote := func(mockConnector *connector.GoMockConnector) {
+		files := map[string]string{
+			"file-a":      "docs/a." + shortHash("old a") + ".md",
+			"file-b":      "b." + shortHash("b") + ".md",
+			"file-c":      "c." + shortHash("c") + ".md",
+			"file-manual": "manual.pdf",
+		}
+
+		mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+			Method: http.MethodGet,
+			URL:    "/assistants/asst-1",
+			CustomHeader: map[string]string{
+				connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+			},
+		}, gomock.Any()).Return(nil).SetArg(2, &repository.Assistant{
+			ID:      "asst-1",
+			FileIDs: []string{"file-manual", "file-a", "file-b", "file-c"},
+		})
+
+		for id, filename := range files {
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files/" + id,
+			}, gomock.Any()).Return(nil).SetArg(2, &repository.File{ID: id, Filename: filename})
+		}
+	}
+
+	changes := func(newFileID string) []response.FileSyncChange {
+		return []response.FileSyncChange{
+			{Action: response.FileSyncUnchanged, Path: "b.md", FileID: "file-b", SHA256: fullHash("b"), Bytes: 1},
+			{Action: response.FileSyncReplace, Path: "docs/a.md", FileID: newFileID, PreviousFileID: "file-a", SHA256: fullHash("new a"), Bytes: 5},
+			{Action: response.FileSyncDelete, Path: "c.md", PreviousFileID: "file-c"},
+		}
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given folder with changed and removed files, When dry run, Return report without changing anything": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			expectRemote(mockConnector)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				sync: &request.FileSync{
+					AssistantID: "asst-1",
+					DryRun:      true,
+					Dir:         newFolder(t),
+					Extensions:  []string{".md"},
+				},
+				want: &response.FileSyncReport{
+					AssistantID: "asst-1",
+					DryRun:      true,
+					Changes:     changes(""),
+				},
+			}
+		},
+		"Given folder with changed and removed files, When sync is applied, Return report with file ids of assistant": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			expectRemote(mockConnector)
+
+			isUpload := gomock.Cond(func(x any) bool {
+				option := x.(*connector.RequestOption)
+				return option.Method == http.MethodPost && option.URL == "/files"
+			})
+
+			isUpdate := gomock.Cond(func(x any) bool {
+				option := x.(*connector.RequestOption)
+				if option.Method != http.MethodPost || option.URL != "/assistants/asst-1" {
+					return false
+				}
+
+				body, _ := io.ReadAll(option.Body)
+
+				return strings.TrimSpace(string(body)) == `{"file_ids":["file-manual","file-b","file-a2"]}`
+			})
+
+			gomock.InOrder(
+				mockConnector.EXPECT().Send(gomock.Any(), isUpload, gomock.Any()).DoAndReturn(
+					func(ctx context.Context, option *connector.RequestOption, result any) error {
+						assert.Equal(t, "docs/a."+shortHash("new a")+".md", option.Multipart[1].Filename)
+						assert.Equal(t, int64(5), option.Multipart[1].Size)
+
+						*(result.(**repository.File)) = &repository.File{ID: "file-a2"}
+
+						return nil
+					}),
+				mockConnector.EXPECT().Send(gomock.Any(), isUpdate, gomock.Any()).Return(nil).SetArg(2, &repository.Assistant{ID: "asst-1"}),
+				mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+					Method: http.MethodDelete,
+					URL:    "/files/file-a",
+				}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{Deleted: true}),
+				mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+					Method: http.MethodDelete,
+					URL:    "/files/file-c",
+				}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{Deleted: true}),
+			)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				sync: &request.FileSync{
+					AssistantID: "asst-1",
+					Dir:         newFolder(t),
+					Extensions:  []string{".md"},
+				},
+				want: &response.FileSyncReport{
+					AssistantID: "asst-1",
+					Changes:     changes("file-a2"),
+					FileIDs:     []string{"file-manual", "file-b", "file-a2"},
+				},
+			}
+		},
+		"Given folder and failing update of assistant, When sync is applied, Return error and delete uploaded file": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			expectRemote(mockConnector)
+
+			errUpdate := errors.New("update failed")
+
+			gomock.InOrder(
+				mockConnector.EXPECT().Send(gomock.Any(), gomock.Cond(func(x any) bool {
+					return x.(*connector.RequestOption).URL == "/files"
+				}), gomock.Any()).Return(nil).SetArg(2, &repository.File{ID: "file-a2"}),
+				mockConnector.EXPECT().Send(gomock.Any(), gomock.Cond(func(x any) bool {
+					return x.(*connector.RequestOption).URL == "/assistants/asst-1"
+				}), gomock.Any()).Return(errUpdate),
+				mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+					Method: http.MethodDelete,
+					URL:    "/files/file-a2",
+				}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{Deleted: true}),
+			)
+
+			return test{
+				fields: fileFields{connector: mockConnector},
+				sync: &request.FileSync{
+					AssistantID: "asst-1",
+					Dir:         newFolder(t),
+					Extensions:  []string{".md"},
+				},
+				wantErr: errUpdate,
+			}
+		},
+		"Given request without assistant, When sync files, Return invalid file sync error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields:  fileFields{connector: connector.NewGoMockConnector(ctrl)},
+				sync:    &request.FileSync{Dir: t.TempDir()},
+				wantErr: usecases.ErrFileSyncInvalid,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
 		t.Run(name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
@@ -74,7 +764,7 @@ func TestFileUsecase_GetListFile(t *testing.T) {
 
 			sut := fileSut(tt.fields)
 
-			got, err := sut.GetListFile(tt.args.ctx)
+			got, err := sut.SyncFiles(context.Background(), tt.sync)
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
@@ -2,21 +2,47 @@ package usecases
 
 import (
 	"context"
+	"errors"
 
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 )
 
+var (
+	ErrFileInvalid     = errors.New("invalid file")
+	ErrFileSyncInvalid = errors.New("invalid file sync")
+)
+
+const (
+	// defaultFileContentType is the content type of file content when OpenAI does not send it
+	defaultFileContentType = "application/octet-stream"
+	// maxFileListLimit is the maximum page size to list files allowed by OpenAI
+	maxFileListLimit = 10000
+	// fileSyncPurpose is the purpose of files uploaded by sync, they are used by assistant
+	fileSyncPurpose = "assistants"
+	// fileSyncHashLength is the length of hex SHA-256 prefix kept in the name of synced file
+	fileSyncHashLength = 16
+)
+
 type FileUsecase interface {
-	GetListFile(ctx context.Context) (*[]repository.File, error)
+	GetListFile(ctx context.Context, option *request.ListFile) (*response.List[repository.File], error)
+	UploadFile(ctx context.Context, upload *request.UploadFile) (*repository.File, error)
+	GetFile(ctx context.Context, fileID string) (*repository.File, error)
+	DeleteFile(ctx context.Context, fileID string) error
+	GetFileContent(ctx context.Context, fileID string) (*response.FileContent, error)
+	SyncFiles(ctx context.Context, sync *request.FileSync) (*response.FileSyncReport, error)
 }
 
-func NewFileUsecase(connector connector.Connector) FileUsecase {
+func NewFileUsecase(connector connector.Connector, assistantUsecase AssistantUsecase) FileUsecase {
 	return &fileUsecase{
-		connector: connector,
+		connector:        connector,
+		assistantUsecase: assistantUsecase,
 	}
 }
 
 type fileUsecase struct {
-	connector connector.Connector
+	connector        connector.Connector
+	assistantUsecase AssistantUsecase
 }
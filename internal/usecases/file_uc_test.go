@@ -12,5 +12,6 @@ type fileFields struct {
 func fileSut(f fileFields) usecases.FileUsecase {
 	return usecases.NewFileUsecase(
 		f.connector,
+		usecases.NewAssistantUsecase(f.connector),
 	)
 }
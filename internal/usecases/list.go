@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/yonisaka/assistant/internal/entities/request"
+)
+
+const (
+	listOrderAsc  = "asc"
+	listOrderDesc = "desc"
+)
+
+var ErrListOptionInvalid = errors.New("invalid list option")
+
+// listQuery is a function to validate cursor pagination from client and convert it into query of OpenAI API
+// Limit must be between 1 and maxLimit when it is set, only the set options are added to the query
+func listQuery(option request.ListOption, maxLimit int) (url.Values, error) {
+	query := url.Values{}
+
+	if option.Limit < 0 || option.Limit > maxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrListOptionInvalid, maxLimit)
+	}
+
+	if option.Limit > 0 {
+		query.Set("limit", strconv.Itoa(option.Limit))
+	}
+
+	switch option.Order {
+	case "":
+	case listOrderAsc, listOrderDesc:
+		query.Set("order", option.Order)
+	default:
+		return nil, fmt.Errorf("%w: order must be %s or %s", ErrListOptionInvalid, listOrderAsc, listOrderDesc)
+	}
+
+	if option.After != "" {
+		query.Set("after", option.After)
+	}
+
+	if option.Before != "" {
+		query.Set("before", option.Before)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+)
+
+// GetListMessage is a function to get a page of messages of the owner's thread from OpenAI API
+// Messages are sent from the newest by default, order asc sends them from the oldest
+func (u *promptUsecase) GetListMessage(ctx context.Context, option *request.ListMessage) (*response.List[response.Message], error) {
+	query, err := listQuery(option.ListOption, messageListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := getOwnedConversation(ctx, u.conversationRepository, option.ThreadID, option.OwnerID); err != nil {
+		return nil, err
+	}
+
+	result, err := u.listMessages(ctx, option.ThreadID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]response.Message, 0, len(result.Data))
+	for i := range result.Data {
+		messages = append(messages, toMessage(option.ThreadID, &result.Data[i]))
+	}
+
+	list := &response.List[response.Message]{
+		Data:    messages,
+		HasMore: result.HasMore,
+		FirstID: result.FirstID,
+		LastID:  result.LastID,
+	}
+
+	if len(messages) > 0 {
+		if list.FirstID == "" {
+			list.FirstID = messages[0].ID
+		}
+
+		if list.LastID == "" {
+			list.LastID = messages[len(messages)-1].ID
+		}
+	}
+
+	return list, nil
+}
+
+// toMessage is a function to convert OpenAI message into message sent to client
+// Text parts are joined by a blank line and their footnotes are kept in order
+func toMessage(threadID string, message *repository.Message) response.Message {
+	resolveMessage(threadID, message)
+
+	result := response.Message{
+		ID:        message.ID,
+		Role:      message.Role,
+		RunID:     message.RunID,
+		CreatedAt: message.CreatedAt,
+	}
+
+	texts := make([]string, 0, len(message.Content))
+
+	for _, content := range message.Content {
+		switch {
+		case content.Type == repository.ContentTypeText && content.Text != nil:
+			texts = append(texts, content.Text.Value)
+			result.Footnotes = append(result.Footnotes, content.Text.Footnotes...)
+		case content.Type == repository.ContentTypeImageFile && content.ImageFile != nil:
+			result.Images = append(result.Images, response.MessageFile{
+				FileID: content.ImageFile.FileID,
+				URL:    content.ImageFile.URL,
+			})
+		case content.Type == repository.ContentTypeImageURL && content.ImageURL != nil:
+			result.Images = append(result.Images, response.MessageFile{URL: content.ImageURL.URL})
+		}
+	}
+
+	result.Text = strings.Join(texts, "\n\n")
+
+	for _, fileID := range message.FileIDS {
+		result.Attachments = append(result.Attachments, response.MessageFile{FileID: fileID, URL: fileURL(threadID, fileID)})
+	}
+
+	return result
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Interval is a function to get delay before the next check, it grows by multiplier up to max interval
+func (s *backoffPollStrategy) Interval(attempt int) time.Duration {
+	interval := float64(s.config.InitialInterval)
+
+	if s.config.Multiplier > 1 && attempt > 1 {
+		interval *= math.Pow(s.config.Multiplier, float64(attempt-1))
+	}
+
+	if s.config.MaxInterval > 0 && interval > float64(s.config.MaxInterval) {
+		return s.config.MaxInterval
+	}
+
+	return time.Duration(interval)
+}
+
+// MaxWait is a function to get upper bound of polling a run
+func (s *backoffPollStrategy) MaxWait() time.Duration {
+	return s.config.MaxWait
+}
+
+// pollDeadline is a function to get the time polling a run must stop
+// It is the earliest of max wait from start and the run expiration, zero time means no deadline
+func pollDeadline(strategy PollStrategy, start time.Time, expiresAt int64) time.Time {
+	var deadline time.Time
+
+	if maxWait := strategy.MaxWait(); maxWait > 0 {
+		deadline = start.Add(maxWait)
+	}
+
+	if expiresAt > 0 {
+		expiration := time.Unix(expiresAt, 0)
+		if deadline.IsZero() || expiration.Before(deadline) {
+			deadline = expiration
+		}
+	}
+
+	return deadline
+}
+
+// waitPoll is a function to wait before the next check
+// It returns ErrRunPollTimeout when the next check would be after the deadline
+// and the context error as soon as the context is done
+func waitPoll(ctx context.Context, interval time.Duration, deadline time.Time) error {
+	if !deadline.IsZero() && time.Now().Add(interval).After(deadline) {
+		return ErrRunPollTimeout
+	}
+
+	if interval <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
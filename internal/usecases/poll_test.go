@@ -0,0 +1,66 @@
+package usecases_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"testing"
+	"time"
+)
+
+func TestBackoffPollStrategy_Interval(t *testing.T) {
+	type test struct {
+		config usecases.PollConfig
+		want   []time.Duration
+	}
+
+	tests := map[string]test{
+		"Given multiplier, When attempt grows, Return growing interval up to max interval": {
+			config: usecases.PollConfig{
+				InitialInterval: 100 * time.Millisecond,
+				Multiplier:      2,
+				MaxInterval:     500 * time.Millisecond,
+			},
+			want: []time.Duration{
+				100 * time.Millisecond,
+				200 * time.Millisecond,
+				400 * time.Millisecond,
+				500 * time.Millisecond,
+			},
+		},
+		"Given multiplier without max interval, When interval would overflow, Return default max interval": {
+			config: usecases.PollConfig{
+				InitialInterval: time.Second,
+				Multiplier:      1e6,
+			},
+			want: []time.Duration{
+				time.Second,
+				usecases.DefaultPollMaxInterval,
+				usecases.DefaultPollMaxInterval,
+				usecases.DefaultPollMaxInterval,
+			},
+		},
+		"Given no multiplier, When attempt grows, Return constant interval": {
+			config: usecases.PollConfig{
+				InitialInterval: 500 * time.Millisecond,
+			},
+			want: []time.Duration{
+				500 * time.Millisecond,
+				500 * time.Millisecond,
+				500 * time.Millisecond,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := usecases.NewBackoffPollStrategy(tt.config)
+
+			got := make([]time.Duration, 0, len(tt.want))
+			for attempt := 1; attempt <= len(tt.want); attempt++ {
+				got = append(got, sut.Interval(attempt))
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
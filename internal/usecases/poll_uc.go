@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+)
+
+// PollStrategy is an interface to decide how run status is polled
+// It is injected so the delay can be tuned by config and removed in test
+type PollStrategy interface {
+	// Interval returns the delay before the next check, attempt starts from 1 after the first check
+	Interval(attempt int) time.Duration
+	// MaxWait returns the upper bound of polling a run, zero means it is only bounded by the run expiration
+	MaxWait() time.Duration
+}
+
+// PollConfig is a struct to set backoff poll strategy
+type PollConfig struct {
+	InitialInterval time.Duration
+	// Multiplier is the growth factor of interval on every check, lower than 1 means constant interval
+	Multiplier float64
+	// MaxInterval caps the growing interval, it falls back to DefaultPollMaxInterval when multiplier is set without it
+	MaxInterval time.Duration
+	MaxWait     time.Duration
+}
+
+func NewBackoffPollStrategy(config PollConfig) PollStrategy {
+	// Interval without cap overflows after enough attempts and would poll with no delay
+	if config.Multiplier > 1 && config.MaxInterval <= 0 {
+		config.MaxInterval = max(config.InitialInterval, DefaultPollMaxInterval)
+	}
+
+	return &backoffPollStrategy{
+		config: config,
+	}
+}
+
+type backoffPollStrategy struct {
+	config PollConfig
+}
+
+const (
+	DefaultPollInitialInterval = 250 * time.Millisecond
+	DefaultPollMultiplier      = 1.5
+	DefaultPollMaxInterval     = 2 * time.Second
+	DefaultPollMaxWait         = 5 * time.Minute
+)
+
+var ErrRunPollTimeout = errors.New("run did not finish before max wait")
+
+// DefaultPollConfig is a function to get recommended poll config
+func DefaultPollConfig() PollConfig {
+	return PollConfig{
+		InitialInterval: DefaultPollInitialInterval,
+		Multiplier:      DefaultPollMultiplier,
+		MaxInterval:     DefaultPollMaxInterval,
+		MaxWait:         DefaultPollMaxWait,
+	}
+}
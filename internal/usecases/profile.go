@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"golang.org/x/time/rate"
+)
+
+// Get is a function to get copy of the profile by its name
+func (r *profileRegistry) Get(name string) (*Profile, error) {
+	profile, ok := r.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	result := *profile
+
+	return &result, nil
+}
+
+// Allow is a function to take a request from the rate limit of the profile
+func (r *profileRegistry) Allow(name string) error {
+	if _, ok := r.profiles[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+
+	limiter, ok := r.limiters[name]
+	if ok && !limiter.Allow() {
+		return fmt.Errorf("%w: %s", ErrProfileRateLimited, name)
+	}
+
+	return nil
+}
+
+// List is a function to get all profiles sorted by name
+func (r *profileRegistry) List() []Profile {
+	profiles := make([]Profile, 0, len(r.profiles))
+	for _, profile := range r.profiles {
+		profiles = append(profiles, *profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles
+}
+
+// AllowsFile is a function to check if the prompt of the profile can use the file
+func (p *Profile) AllowsFile(fileID string) bool {
+	return slices.Contains(p.FileIDs, fileID)
+}
+
+// register is a function to validate the profile and add it to the registry
+func (r *profileRegistry) register(profile Profile, toolRegistry ToolRegistry) error {
+	if profile.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrProfileInvalid)
+	}
+
+	if _, ok := r.profiles[profile.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrProfileExists, profile.Name)
+	}
+
+	if profile.AssistantID == "" {
+		return fmt.Errorf("%w: %s: assistant_id is required", ErrProfileInvalid, profile.Name)
+	}
+
+	if profile.RateLimit.RequestsPerMinute < 0 || profile.RateLimit.Burst < 0 {
+		return fmt.Errorf("%w: %s: rate limit must not be negative", ErrProfileInvalid, profile.Name)
+	}
+
+	for _, name := range profile.Tools {
+		if _, err := toolDefinition(name, toolRegistry); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrProfileInvalid, profile.Name, err)
+		}
+	}
+
+	r.profiles[profile.Name] = &profile
+
+	if limit := profile.RateLimit; limit.RequestsPerMinute > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = limit.RequestsPerMinute
+		}
+
+		r.limiters[profile.Name] = rate.NewLimiter(rate.Every(time.Minute/time.Duration(limit.RequestsPerMinute)), burst)
+	}
+
+	return nil
+}
+
+// toolDefinition is a function to get tool definition by its name
+// Built-in tool has only its type, function tool uses the definition in tool registry so it can be executed
+func toolDefinition(name string, toolRegistry ToolRegistry) (repository.AssistantTool, error) {
+	if name == connector.OpenAIToolTypeCodeInterpreter || name == connector.OpenAIToolTypeRetrieval {
+		return repository.AssistantTool{Type: name}, nil
+	}
+
+	if toolRegistry != nil {
+		for _, tool := range toolRegistry.Definitions() {
+			if tool.Function != nil && tool.Function.Name == name {
+				return tool, nil
+			}
+		}
+	}
+
+	return repository.AssistantTool{}, fmt.Errorf("%w: tool %s is not registered", ErrRunOptionInvalid, name)
+}
@@ -0,0 +1,93 @@
+package usecases_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"testing"
+)
+
+func TestNewProfileRegistry(t *testing.T) {
+	type test struct {
+		profiles []usecases.Profile
+		wantErr  error
+	}
+
+	tests := map[string]test{
+		"Given valid profiles, When registry is built, Return no error": {
+			profiles: []usecases.Profile{
+				{Name: "support", AssistantID: "asst-support", Tools: []string{"retrieval", "get_current_time"}},
+				{Name: "sales", AssistantID: "asst-sales"},
+			},
+		},
+		"Given profile without assistant, When registry is built, Return invalid error": {
+			profiles: []usecases.Profile{{Name: "support"}},
+			wantErr:  usecases.ErrProfileInvalid,
+		},
+		"Given profiles with the same name, When registry is built, Return exists error": {
+			profiles: []usecases.Profile{
+				{Name: "support", AssistantID: "asst-support"},
+				{Name: "support", AssistantID: "asst-other"},
+			},
+			wantErr: usecases.ErrProfileExists,
+		},
+		"Given profile with unregistered tool, When registry is built, Return invalid error": {
+			profiles: []usecases.Profile{{Name: "support", AssistantID: "asst-support", Tools: []string{"get_weather"}}},
+			wantErr:  usecases.ErrProfileInvalid,
+		},
+		"Given profile with negative rate limit, When registry is built, Return invalid error": {
+			profiles: []usecases.Profile{{
+				Name:        "support",
+				AssistantID: "asst-support",
+				RateLimit:   usecases.ProfileRateLimit{RequestsPerMinute: -1},
+			}},
+			wantErr: usecases.ErrProfileInvalid,
+		},
+	}
+
+	toolRegistry := usecases.NewToolRegistry()
+	require.NoError(t, toolRegistry.Register(usecases.CurrentTimeTool()))
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := usecases.NewProfileRegistry(tt.profiles, toolRegistry)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, got.List(), len(tt.profiles))
+		})
+	}
+}
+
+func TestProfileRegistry_Allow(t *testing.T) {
+	sut, err := usecases.NewProfileRegistry([]usecases.Profile{
+		{
+			Name:        "support",
+			AssistantID: "asst-support",
+			RateLimit:   usecases.ProfileRateLimit{RequestsPerMinute: 1, Burst: 2},
+		},
+		{Name: "sales", AssistantID: "asst-sales"},
+	}, nil)
+	require.NoError(t, err)
+
+	t.Run("Given limited profile, When burst is used, Return rate limited error", func(t *testing.T) {
+		assert.NoError(t, sut.Allow("support"))
+		assert.NoError(t, sut.Allow("support"))
+		assert.ErrorIs(t, sut.Allow("support"), usecases.ErrProfileRateLimited)
+	})
+
+	t.Run("Given profile without rate limit, When it is used many times, Return no error", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			require.NoError(t, sut.Allow("sales"))
+		}
+	})
+
+	t.Run("Given unknown profile, When it is used, Return not found error", func(t *testing.T) {
+		assert.ErrorIs(t, sut.Allow("marketing"), usecases.ErrProfileNotFound)
+	})
+}
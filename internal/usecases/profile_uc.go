@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"errors"
+
+	"golang.org/x/time/rate"
+)
+
+// Profile is a struct of named assistant setup a prompt can choose, e.g. support or sales
+// The prompt runs with the assistant, instructions, model and tools of the profile
+type Profile struct {
+	Name         string `json:"name" yaml:"name"`
+	AssistantID  string `json:"assistant_id" yaml:"assistant_id"`
+	Instructions string `json:"instructions" yaml:"instructions"`
+	Model        string `json:"model" yaml:"model"`
+	// Tools are names of built-in tool type, e.g. retrieval, or function in tool registry
+	Tools []string `json:"tools" yaml:"tools"`
+	// FileIDs are files the prompt of this profile is allowed to use
+	FileIDs   []string         `json:"file_ids" yaml:"file_ids"`
+	RateLimit ProfileRateLimit `json:"rate_limit" yaml:"rate_limit"`
+}
+
+// ProfileRateLimit is a struct to limit runs of a profile, shared by all its clients
+// Zero requests per minute means the profile is not limited
+type ProfileRateLimit struct {
+	RequestsPerMinute int `json:"requests_per_minute" yaml:"requests_per_minute"`
+	// Burst is the number of requests allowed at once, it is requests per minute when empty
+	Burst int `json:"burst" yaml:"burst"`
+}
+
+// ProfileRegistry is an interface to find profile by its name and limit its usage
+type ProfileRegistry interface {
+	// Get returns the profile, it returns ErrProfileNotFound when the name is not registered
+	Get(name string) (*Profile, error)
+	// Allow returns ErrProfileRateLimited when the profile has reached its rate limit
+	Allow(name string) error
+	// List returns all profiles sorted by name
+	List() []Profile
+}
+
+// NewProfileRegistry is a function to validate profiles and build their registry
+// Tools of profile must be built-in tool or registered in the tool registry
+func NewProfileRegistry(profiles []Profile, toolRegistry ToolRegistry) (ProfileRegistry, error) {
+	registry := &profileRegistry{
+		profiles: make(map[string]*Profile, len(profiles)),
+		limiters: make(map[string]*rate.Limiter, len(profiles)),
+	}
+
+	for i := range profiles {
+		if err := registry.register(profiles[i], toolRegistry); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+type profileRegistry struct {
+	profiles map[string]*Profile
+	limiters map[string]*rate.Limiter
+}
+
+var (
+	ErrProfileInvalid     = errors.New("profile is invalid")
+	ErrProfileExists      = errors.New("profile already exists")
+	ErrProfileNotFound    = errors.New("profile not found")
+	ErrProfileRateLimited = errors.New("profile rate limit exceeded")
+)
@@ -4,84 +4,402 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 )
 
+const (
+	// cancelRunTimeout is the time limit to cancel run after the client is gone
+	cancelRunTimeout = 10 * time.Second
+
+	maxConversationTitleLength = 50
+
+	// messageListLimit is the page size to list messages, it is the maximum allowed by OpenAI
+	messageListLimit     = 100
+	messageListOrderAsc  = "asc"
+	messageRoleAssistant = "assistant"
+)
+
+// PreparePrompt is a function to check the prompt can be run before it is sent to OpenAI
+// It applies the run policy, validates attachments, gets the conversation of the owner and attaches the files
+// The stored conversation is found first, so a continued conversation runs with the profile it is started with
+func (u *promptUsecase) PreparePrompt(ctx context.Context, prompt *request.Prompt) (*PreparedPrompt, error) {
+	conversation, err := u.findConversation(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resumeProfile(prompt, conversation); err != nil {
+		return nil, err
+	}
+
+	runRequest, err := u.runPolicy.RunRequest(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateAttachments(prompt); err != nil {
+		return nil, err
+	}
+
+	conversation, err = u.getConversation(ctx, prompt, runRequest, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := u.attachFiles(ctx, prompt, conversation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedPrompt{
+		prompt:       prompt,
+		runRequest:   runRequest,
+		conversation: conversation,
+		attachments:  attachments,
+	}, nil
+}
+
 // SendPrompt is a function to send prompt to OpenAI with several steps below:
-// 1. Create Thread
+// 1. Get Conversation, a new conversation creates its own thread
 // 2. Create Message
 // 3. Run Thread
 // 4. Run Status Thread
 // 5. Get Prompt Response
-func (u *promptUsecase) SendPrompt(ctx context.Context, message string) (*repository.Message, error) {
-	threadID, err := u.createThread(ctx)
+func (u *promptUsecase) SendPrompt(ctx context.Context, prompt *request.Prompt) (*response.Prompt, error) {
+	prepared, err := u.PreparePrompt(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation, attachments := prepared.conversation, prepared.attachments
+	threadID := conversation.ThreadID
+
+	err = u.createMessage(ctx, threadID, prompt.Message, attachmentFileIDs(attachments))
+	if err != nil {
+		return nil, err
+	}
+
+	runID, err := u.runThread(ctx, threadID, prepared.runRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	promptResponse, err := u.waitPromptResponse(ctx, threadID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.touchConversation(ctx, conversation, messagesFileIDs(promptResponse)...)
+
+	return &response.Prompt{
+		ConversationID: conversation.ID,
+		ThreadID:       threadID,
+		RunID:          runID,
+		Messages:       promptResponse,
+		Attachments:    attachments,
+	}, nil
+}
+
+// SubmitPrompt is a function to send prompt to OpenAI and drive the run in background with several steps below:
+// 1. Get Conversation, a new conversation creates its own thread
+// 2. Create Message
+// 3. Run Thread
+// 4. Run Status Thread and Get Prompt Response in background, the result is fetched by GetRun
+// The slot of background run is taken before anything is sent to OpenAI,
+// so it returns ErrTooManyRuns instead of starting run that nobody drives
+func (u *promptUsecase) SubmitPrompt(ctx context.Context, prompt *request.Prompt) (*response.Run, error) {
+	if !u.asyncRuns.acquire() {
+		return nil, ErrTooManyRuns
+	}
+
+	driven := false
+	defer func() {
+		if !driven {
+			u.asyncRuns.release()
+		}
+	}()
+
+	prepared, err := u.PreparePrompt(ctx, prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	err = u.createMessage(ctx, threadID, message)
+	conversation, attachments := prepared.conversation, prepared.attachments
+	threadID := conversation.ThreadID
+
+	err = u.createMessage(ctx, threadID, prompt.Message, attachmentFileIDs(attachments))
 	if err != nil {
 		return nil, err
 	}
 
-	runID, err := u.runThread(ctx, threadID)
+	runID, err := u.runThread(ctx, threadID, prepared.runRequest)
 	if err != nil {
 		return nil, err
 	}
 
-	err = u.runStatus(ctx, threadID, runID)
+	run := response.Run{
+		ID:             runID,
+		ConversationID: conversation.ID,
+		ThreadID:       threadID,
+		Status:         connector.OpenAIStatusQueued,
+		Attachments:    attachments,
+		CreatedAt:      time.Now().Unix(),
+	}
+
+	u.asyncRuns.add(prompt.OwnerID, run)
+
+	driven = true
+	go u.driveRun(conversation, runID)
+
+	return &run, nil
+}
+
+// GetRun is a function to get status and output of run submitted by SubmitPrompt
+func (u *promptUsecase) GetRun(_ context.Context, runID, ownerID string) (*response.Run, error) {
+	return u.asyncRuns.get(runID, ownerID)
+}
+
+// driveRun is a function to wait the submitted run until it is finished and keep its result
+// It is not bound to the request context, the run is bounded by the poll strategy instead
+// It gives back the slot of background run taken by SubmitPrompt
+func (u *promptUsecase) driveRun(conversation *repository.Conversation, runID string) {
+	defer u.asyncRuns.release()
+
+	ctx := context.Background()
+	threadID := conversation.ThreadID
+
+	u.asyncRuns.update(runID, func(run *response.Run) {
+		run.Status = connector.OpenAIStatusInProgress
+	})
+
+	messages, err := u.waitPromptResponse(ctx, threadID, runID)
+
+	u.asyncRuns.update(runID, func(run *response.Run) {
+		run.FinishedAt = time.Now().Unix()
+
+		switch runErr, ok := connector.AsRunError(err); {
+		case err == nil:
+			run.Status = connector.OpenAIStatusCompleted
+			run.Messages = messages
+		case ok:
+			run.Status = runErr.Status
+			run.Error = runErr.Error()
+		default:
+			run.Status = connector.OpenAIStatusFailed
+			run.Error = err.Error()
+		}
+	})
+
 	if err != nil {
+		log.Warnw("Async Run Failed:", "id", runID, "error", err)
+		return
+	}
+
+	u.touchConversation(ctx, conversation, messagesFileIDs(messages)...)
+}
+
+// waitPromptResponse is a function to wait the run and get its prompt response
+// Run that is not finished when waiting fails, e.g. the caller is gone, the wait times out or a tool fails,
+// is cancelled instead of left running for nobody
+func (u *promptUsecase) waitPromptResponse(ctx context.Context, threadID, runID string) ([]repository.Message, error) {
+	if err := u.runStatus(ctx, threadID, runID); err != nil {
+		if _, ok := connector.AsRunError(err); !ok {
+			u.cancelRun(threadID, runID)
+		}
+
 		return nil, err
 	}
 
-	promptResponse, err := u.getPromptResponse(ctx, threadID)
+	return u.getPromptResponse(ctx, threadID, runID)
+}
+
+// findConversation is a function to get stored conversation of the owner the prompt continues
+// It returns nil conversation when the prompt starts new conversation
+func (u *promptUsecase) findConversation(ctx context.Context, prompt *request.Prompt) (*repository.Conversation, error) {
+	var (
+		conversation *repository.Conversation
+		err          error
+	)
+
+	switch {
+	case prompt.ConversationID != "":
+		conversation, err = u.conversationRepository.Get(ctx, prompt.ConversationID)
+	case prompt.ThreadID != "":
+		conversation, err = u.conversationRepository.GetByThreadID(ctx, prompt.ThreadID)
+	default:
+		return nil, nil
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	return promptResponse, err
+	// Conversation of other owner is reported as not found, so its existence is not leaked
+	if conversation.OwnerID != prompt.OwnerID {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// resumeProfile is a function to apply the profile of the conversation to the prompt that continues it
+// so the prompt can not leave the rate limit, tools and instructions of the profile by omitting it
+// The prompt can not choose other profile than the conversation is started with
+func resumeProfile(prompt *request.Prompt, conversation *repository.Conversation) error {
+	if conversation == nil || conversation.Profile == "" {
+		return nil
+	}
+
+	if prompt.Profile == "" {
+		prompt.Profile = conversation.Profile
+		return nil
+	}
+
+	if prompt.Profile != conversation.Profile {
+		return fmt.Errorf("%w: profile %s in conversation of profile %s", ErrRunOptionNotAllowed, prompt.Profile, conversation.Profile)
+	}
+
+	return nil
 }
 
-// createThread is a function to create thread in OpenAI
-// It will check if last thread is still active or not
-// If active, it will return the last thread ID
-// If not active, it will create new thread and return the new thread ID
+// getConversation is a function to get conversation of the prompt
+// If the prompt continues a conversation, it will return the stored conversation found by findConversation
+// and the run keeps the assistant of the conversation unless the prompt chooses one
+// If not, it will create new conversation with its own thread
+func (u *promptUsecase) getConversation(
+	ctx context.Context,
+	prompt *request.Prompt,
+	runRequest *connector.RequestRun,
+	conversation *repository.Conversation,
+) (*repository.Conversation, error) {
+	if conversation == nil {
+		return u.createConversation(ctx, prompt, runRequest.AssistantID)
+	}
+
+	if prompt.AssistantID == "" && prompt.Profile == "" && conversation.AssistantID != "" {
+		runRequest.AssistantID = conversation.AssistantID
+	}
+
+	return conversation, nil
+}
+
+// getOwnedConversation is a function to get conversation of the owner's thread
+// Thread of other owner is reported as not found, so its existence is not leaked
+func getOwnedConversation(
+	ctx context.Context,
+	conversationRepository repository.ConversationRepository,
+	threadID, ownerID string,
+) (*repository.Conversation, error) {
+	conversation, err := conversationRepository.GetByThreadID(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if conversation.OwnerID != ownerID {
+		return nil, repository.ErrConversationNotFound
+	}
+
+	return conversation, nil
+}
+
+// createConversation is a function to create new thread in OpenAI and store it as new conversation
+func (u *promptUsecase) createConversation(ctx context.Context, prompt *request.Prompt, assistantID string) (*repository.Conversation, error) {
+	threadID, err := u.createThread(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation := &repository.Conversation{
+		ID:          uuid.NewString(),
+		OwnerID:     prompt.OwnerID,
+		ThreadID:    threadID,
+		AssistantID: assistantID,
+		Profile:     prompt.Profile,
+		Title:       conversationTitle(prompt.Message),
+	}
+
+	if err := u.conversationRepository.Create(ctx, conversation); err != nil {
+		return nil, err
+	}
+
+	log.Infow("Conversation Created:", "id", conversation.ID, "thread_id", threadID)
+
+	return conversation, nil
+}
+
+// touchConversation is a function to mark conversation as updated after a prompt and add files uploaded with it
+// Only those are changed, so metadata changed by the owner while the run is in flight is kept
+// Failure is only logged because the prompt itself has been answered
+func (u *promptUsecase) touchConversation(ctx context.Context, conversation *repository.Conversation, fileIDs ...string) {
+	_, err := u.conversationRepository.Modify(ctx, conversation.ID, func(stored *repository.Conversation) {
+		for _, fileID := range fileIDs {
+			if !slices.Contains(stored.FileIDs, fileID) {
+				stored.FileIDs = append(stored.FileIDs, fileID)
+			}
+		}
+	})
+	if err != nil {
+		log.Warnw("Conversation Update Failed:", "id", conversation.ID, "error", err)
+	}
+}
+
+// conversationTitle is a function to get conversation title from the first message
+func conversationTitle(message string) string {
+	title := []rune(strings.TrimSpace(message))
+	if len(title) > maxConversationTitleLength {
+		return string(title[:maxConversationTitleLength]) + "..."
+	}
+
+	return string(title)
+}
+
+// createThread is a function to create new thread in OpenAI
+// Every conversation has its own thread, so messages never leak between conversations
 func (u *promptUsecase) createThread(ctx context.Context) (string, error) {
 	httpRequestOption := &connector.RequestOption{
-		Method: http.MethodGet,
+		Method: http.MethodPost,
 		URL:    "/threads",
 		CustomHeader: map[string]string{
 			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
 		},
 	}
 
-	var result *connector.OpenAIThread
+	var result *repository.Thread
 	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
 		return "", err
 	}
 
-	if result == nil {
+	if result == nil || result.ID == "" {
 		return "", connector.ErrCreateThread
 	}
 
-	log.Infow("Thread Created:", "id", result.FirstID)
+	log.Infow("Thread Created:", "id", result.ID)
 
-	return result.FirstID, nil
+	return result.ID, nil
 }
 
 // createMessage is a function to create message in OpenAI
-// It will create message in the last thread from createThread function
-func (u *promptUsecase) createMessage(ctx context.Context, threadID, message string) error {
+// It will create message in the thread of the conversation
+func (u *promptUsecase) createMessage(ctx context.Context, threadID, message string, fileIDs []string) error {
 	requestBodyMessage := connector.RequestMessage{
 		Role:    "user",
 		Content: message,
+		FileIDs: fileIDs,
 	}
 
 	var bufMessage bytes.Buffer
@@ -113,15 +431,11 @@ func (u *promptUsecase) createMessage(ctx context.Context, threadID, message str
 }
 
 // runThread is a function to run thread in OpenAI
-// It will run the last thread from createThread function
+// It will run the thread of the conversation
 // Using AssistantID that has been set on openAI before
-func (u *promptUsecase) runThread(ctx context.Context, threadID string) (string, error) {
-	requestBodyRun := connector.RequestRun{
-		AssistantID: os.Getenv("OPENAI_ASSISTANT_ID"),
-	}
-
+func (u *promptUsecase) runThread(ctx context.Context, threadID string, runRequest *connector.RequestRun) (string, error) {
 	var bufRun bytes.Buffer
-	if err := json.NewEncoder(&bufRun).Encode(requestBodyRun); err != nil {
+	if err := json.NewEncoder(&bufRun).Encode(runRequest); err != nil {
 		return "", err
 	}
 
@@ -150,48 +464,164 @@ func (u *promptUsecase) runThread(ctx context.Context, threadID string) (string,
 
 // runStatus is a function to check run status in OpenAI
 // It will check the last run status from runThread function
-// If the status is not queued or in progress, it will return the last run status
-// If the status is queued or in progress, it will check the status again until it's not queued or in progress with 500ms delay
+// If the status is requires action, it will submit the tool outputs and check the status again after the interval
+// If the status is queued, in progress or cancelling, it will check the status again after the interval of poll strategy
+// If the status is terminal, it will return nil only when the run is completed,
+// otherwise it will return RunError of failed, cancelled, expired or incomplete run
+// It stops as soon as the context is done, and cancels the run when it is not finished before max wait or expiration
 func (u *promptUsecase) runStatus(ctx context.Context, threadID, runID string) error {
-	httpRequestOption := &connector.RequestOption{
-		Method: http.MethodGet,
-		URL:    fmt.Sprintf("/threads/%s/runs/%s", threadID, runID),
-		CustomHeader: map[string]string{
-			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
-		},
-	}
+	start := time.Now()
 
 	runStep := 1
 	for { //nolint: wsl
-		var result *connector.OpenAIRun
-		if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		result, err := u.getRun(ctx, threadID, runID)
+		if err != nil {
 			return err
 		}
 
-		if result == nil {
-			return connector.ErrRunStatusThread
+		log.Infow("Run Status:", "id", result.ID, "status", result.Status, "step", runStep)
+
+		switch {
+		case result.Status == connector.OpenAIStatusRequiresAction:
+			// The run is checked again after the interval, so run that keeps requiring action is bounded too
+			if err := u.submitToolOutputs(ctx, threadID, runID, result.RequiredAction); err != nil {
+				return err
+			}
+		case result.IsTerminal():
+			logRunFinished(result)
+			return result.Err()
 		}
 
-		log.Infow("Run Status:", "id", result.ID, "status", result.Status, "step", runStep)
+		deadline := pollDeadline(u.pollStrategy, start, result.ExpiresAt)
+		if err := waitPoll(ctx, u.pollStrategy.Interval(runStep), deadline); err != nil {
+			if errors.Is(err, ErrRunPollTimeout) {
+				log.Warnw("Run Poll Timeout:", "id", runID, "step", runStep, "elapsed", time.Since(start))
+			}
 
-		if result.Status != "" &&
-			result.Status != connector.OpenAIStatusQueued &&
-			result.Status != connector.OpenAIStatusInProgress {
-			break
+			return err
 		}
 
 		runStep++
+	}
+}
+
+// getRun is a function to get run of thread in OpenAI
+func (u *promptUsecase) getRun(ctx context.Context, threadID, runID string) (*connector.OpenAIRun, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    fmt.Sprintf("/threads/%s/runs/%s", threadID, runID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	var result *connector.OpenAIRun
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, connector.ErrRunStatusThread
+	}
+
+	return result, nil
+}
+
+// logRunFinished is a function to log terminal run with its token usage
+func logRunFinished(run *connector.OpenAIRun) {
+	if run.Usage == nil {
+		log.Infow("Run Finished:", "id", run.ID, "status", run.Status)
+		return
+	}
+
+	log.Infow("Run Finished:", "id", run.ID, "status", run.Status,
+		"prompt_tokens", run.Usage.PromptTokens,
+		"completion_tokens", run.Usage.CompletionTokens,
+		"total_tokens", run.Usage.TotalTokens,
+	)
+}
+
+// submitToolOutputs is a function to run the tools required by the run and submit their outputs in OpenAI
+func (u *promptUsecase) submitToolOutputs(ctx context.Context, threadID, runID string, requiredAction *connector.RequiredAction) error {
+	httpRequestOption, err := u.toolOutputsRequestOption(ctx, threadID, runID, requiredAction, false)
+	if err != nil {
+		return err
+	}
 
-		time.Sleep(500 * time.Millisecond)
+	var result *connector.OpenAIRun
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return err
 	}
 
+	if result == nil {
+		return connector.ErrSubmitToolCall
+	}
+
+	log.Infow("Tool Outputs Submitted:", "id", result.ID, "status", result.Status)
+
 	return nil
 }
 
-// getPromptResponse is a function to get prompt response in OpenAI
-// It will get the last prompt response from runStatus function
-// We only need the last prompt response
-func (u *promptUsecase) getPromptResponse(ctx context.Context, threadID string) (*repository.Message, error) {
+// submitToolOutputsStream is a function to run the tools required by the run, submit their outputs
+// and stream the continued run events in OpenAI
+func (u *promptUsecase) submitToolOutputsStream(ctx context.Context, threadID, runID string, requiredAction *connector.RequiredAction) (connector.EventStream, error) {
+	httpRequestOption, err := u.toolOutputsRequestOption(ctx, threadID, runID, requiredAction, true)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := u.connector.Stream(ctx, httpRequestOption)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Tool Outputs Submitted:", "id", runID)
+
+	return stream, nil
+}
+
+// toolOutputsRequestOption is a function to execute the tool calls of required action
+// and build the request to submit their outputs
+func (u *promptUsecase) toolOutputsRequestOption(
+	ctx context.Context,
+	threadID, runID string,
+	requiredAction *connector.RequiredAction,
+	stream bool,
+) (*connector.RequestOption, error) {
+	if requiredAction == nil || requiredAction.Type != connector.OpenAIRequiredActionSubmitToolOutputs {
+		return nil, connector.ErrSubmitToolCall
+	}
+
+	requestBodyToolOutputs := connector.RequestToolOutputs{
+		ToolOutputs: u.toolRegistry.Execute(ctx, requiredAction.SubmitToolOutputs.ToolCalls),
+		Stream:      stream,
+	}
+
+	var bufToolOutputs bytes.Buffer
+	if err := json.NewEncoder(&bufToolOutputs).Encode(requestBodyToolOutputs); err != nil {
+		return nil, err
+	}
+
+	return &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("/threads/%s/runs/%s/submit_tool_outputs", threadID, runID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+		Body: &bufToolOutputs,
+	}, nil
+}
+
+// getPromptResponse is a function to get prompt response of the run in OpenAI
+// It will list messages created by the run from the oldest, page by page,
+// and return the assistant messages in the order they are created with annotations resolved
+// A run that creates no message, e.g. only calling tools, returns empty list
+func (u *promptUsecase) getPromptResponse(ctx context.Context, threadID, runID string) ([]repository.Message, error) {
+	query := url.Values{}
+	query.Set("run_id", runID)
+	query.Set("order", messageListOrderAsc)
+	query.Set("limit", strconv.Itoa(messageListLimit))
+
 	httpRequestOption := &connector.RequestOption{
 		Method: http.MethodGet,
 		URL:    fmt.Sprintf("/threads/%s/messages", threadID),
@@ -200,6 +630,42 @@ func (u *promptUsecase) getPromptResponse(ctx context.Context, threadID string)
 		},
 	}
 
+	iterator := connector.NewListIterator[repository.Message](u.connector, httpRequestOption, query)
+
+	messages := make([]repository.Message, 0)
+	for iterator.Next(ctx) {
+		message := iterator.Item()
+
+		// Filter again in case the API ignores run_id, so messages of other runs are not leaked
+		if message.Role == messageRoleAssistant && (message.RunID == "" || message.RunID == runID) {
+			resolveMessage(threadID, &message)
+			messages = append(messages, message)
+		}
+	}
+
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+
+	log.Infow("Prompt Response:", "run_id", runID, "messages", len(messages))
+
+	return messages, nil
+}
+
+// listMessages is a function to get a page of messages of the thread in OpenAI
+func (u *promptUsecase) listMessages(ctx context.Context, threadID string, query url.Values) (*connector.OpenAIMessage, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    fmt.Sprintf("/threads/%s/messages", threadID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	if len(query) > 0 {
+		httpRequestOption.URL += "?" + query.Encode()
+	}
+
 	var result *connector.OpenAIMessage
 	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
 		return nil, err
@@ -209,7 +675,312 @@ func (u *promptUsecase) getPromptResponse(ctx context.Context, threadID string)
 		return nil, connector.ErrGetPrompt
 	}
 
-	log.Infow("Prompt Last Response:", "id", result.Data[0].ID)
+	return result, nil
+}
+
+// StreamPrompt is a function to send prompt prepared by PreparePrompt to OpenAI
+// and relay the run as events with several steps below:
+// 1. Create Message
+// 2. Run Thread with streaming
+// 3. Relay run status, text delta and completed message to send function,
+// tools required by the run are executed and the run continues in a new stream
+// The stream is bounded by max wait of poll strategy, and the upstream run will be cancelled
+// when it fails before finishing, e.g. the client is disconnected, the deadline is reached
+// or OpenAI closes the stream before the run is done
+func (u *promptUsecase) StreamPrompt(ctx context.Context, prepared *PreparedPrompt, send func(event *response.PromptEvent) error) error {
+	conversation, attachments, runRequest := prepared.conversation, prepared.attachments, prepared.runRequest
+	threadID := conversation.ThreadID
+
+	err := u.createMessage(ctx, threadID, prepared.prompt.Message, attachmentFileIDs(attachments))
+	if err != nil {
+		return err
+	}
+
+	if maxWait := u.pollStrategy.MaxWait(); maxWait > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+
+	stream, err := u.runThreadStream(ctx, threadID, runRequest)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		stream.Close()
+	}()
+
+	var (
+		runID    string
+		fileIDs  []string
+		finished bool
+	)
+
+	defer func() {
+		if !finished {
+			u.cancelRun(threadID, runID)
+		}
+	}()
+
+	for { //nolint: wsl
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			if !finished {
+				return fmt.Errorf("%w: stream is closed before the run is done", io.ErrUnexpectedEOF)
+			}
+
+			u.touchConversation(ctx, conversation, fileIDs...)
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		promptEvent, err := toPromptEvent(threadID, event)
+		if err != nil {
+			return err
+		}
+
+		if promptEvent == nil {
+			continue
+		}
+
+		if promptEvent.RunID != "" {
+			runID = promptEvent.RunID
+		}
+
+		promptEvent.ConversationID = conversation.ID
+
+		if promptEvent.Message != nil {
+			fileIDs = append(fileIDs, messagesFileIDs([]repository.Message{*promptEvent.Message})...)
+		}
+
+		// Attachments are sent once with the first event
+		promptEvent.Attachments, attachments = attachments, nil
+
+		if err := send(promptEvent); err != nil {
+			log.Warnw("Stream Aborted:", "thread_id", threadID, "run_id", runID, "error", err)
+			return err
+		}
+
+		// The run is done only by done event or terminal status, so stream closed before either fails the run
+		if event.Type == connector.EventDone {
+			finished = true
+		}
+
+		// Run that ends without completing is reported to the caller after its status is relayed
+		if event.IsRun() {
+			run, err := event.Run()
+			if err != nil {
+				return err
+			}
+
+			if run.IsTerminal() {
+				finished = true
+
+				if event.Type != connector.EventRunCompleted {
+					logRunFinished(run)
+					return run.Err()
+				}
+			}
+		}
+
+		// The run continues in a new stream after the tool outputs are submitted
+		if event.Type == connector.EventRunRequiresAction {
+			run, err := event.Run()
+			if err != nil {
+				return err
+			}
+
+			toolStream, err := u.submitToolOutputsStream(ctx, threadID, runID, run.RequiredAction)
+			if err != nil {
+				return err
+			}
+
+			stream.Close()
+			stream = toolStream
+		}
+	}
+}
+
+// runThreadStream is a function to run thread in OpenAI and stream the run events
+// Using AssistantID that has been set on openAI before
+func (u *promptUsecase) runThreadStream(ctx context.Context, threadID string, runRequest *connector.RequestRun) (connector.EventStream, error) {
+	requestBodyRun := *runRequest
+	requestBodyRun.Stream = true
+
+	var bufRun bytes.Buffer
+	if err := json.NewEncoder(&bufRun).Encode(requestBodyRun); err != nil {
+		return nil, err
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("/threads/%s/runs", threadID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+		Body: &bufRun,
+	}
+
+	stream, err := u.connector.Stream(ctx, httpRequestOption)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infow("Run Stream Created:", "thread_id", threadID)
+
+	return stream, nil
+}
+
+// CancelRun is a function to cancel run of the owner's thread in OpenAI and wait until it is finished
+// The run is usually finished as cancelled, but it may be completed or failed before the cancellation takes effect
+// It will return ErrRunNotCancellable when the run is already finished before it is cancelled
+func (u *promptUsecase) CancelRun(ctx context.Context, threadID, runID, ownerID string) (*response.Run, error) {
+	conversation, err := getOwnedConversation(ctx, u.conversationRepository, threadID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.requestCancelRun(ctx, threadID, runID)
+	if err != nil {
+		// OpenAI rejects cancelling run that is already finished
+		if !connector.IsBadRequest(err) {
+			return nil, err
+		}
+
+		run, getErr := u.getRun(ctx, threadID, runID)
+		if getErr != nil || !run.IsTerminal() {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: run_id=%s status=%s", ErrRunNotCancellable, runID, run.Status)
+	}
+
+	result, err = u.waitRunFinished(ctx, threadID, result)
+	if err != nil {
+		return nil, err
+	}
+
+	logRunFinished(result)
+
+	return &response.Run{
+		ID:             result.ID,
+		ConversationID: conversation.ID,
+		ThreadID:       threadID,
+		Status:         result.Status,
+		CreatedAt:      result.CreatedAt,
+		FinishedAt:     runFinishedAt(result),
+	}, nil
+}
+
+// waitRunFinished is a function to check the run status until it is terminal
+// It is bounded by max wait of poll strategy, it will return ErrRunPollTimeout when the run is still not finished
+func (u *promptUsecase) waitRunFinished(ctx context.Context, threadID string, run *connector.OpenAIRun) (*connector.OpenAIRun, error) {
+	start := time.Now()
+
+	var err error
 
-	return &result.Data[0], nil
+	for runStep := 1; !run.IsTerminal(); runStep++ {
+		deadline := pollDeadline(u.pollStrategy, start, 0)
+		if err = waitPoll(ctx, u.pollStrategy.Interval(runStep), deadline); err != nil {
+			return nil, err
+		}
+
+		run, err = u.getRun(ctx, threadID, run.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return run, nil
+}
+
+// runFinishedAt is a function to get the time the terminal run is finished
+func runFinishedAt(run *connector.OpenAIRun) int64 {
+	for _, finishedAt := range []*int64{run.CancelledAt, run.CompletedAt, run.FailedAt} {
+		if finishedAt != nil {
+			return *finishedAt
+		}
+	}
+
+	return 0
+}
+
+// cancelRun is a function to cancel run in OpenAI without waiting it to be cancelled
+// It uses its own context because the context of the caller may be already cancelled
+func (u *promptUsecase) cancelRun(threadID, runID string) {
+	if runID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cancelRunTimeout)
+	defer cancel()
+
+	if _, err := u.requestCancelRun(ctx, threadID, runID); err != nil {
+		log.Warnw("Run Cancel Failed:", "id", runID, "error", err)
+		return
+	}
+
+	log.Infow("Run Cancelled:", "id", runID)
+}
+
+// requestCancelRun is a function to request cancellation of run in OpenAI
+func (u *promptUsecase) requestCancelRun(ctx context.Context, threadID, runID string) (*connector.OpenAIRun, error) {
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("/threads/%s/runs/%s/cancel", threadID, runID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	var result *connector.OpenAIRun
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, connector.ErrCancelRun
+	}
+
+	return result, nil
+}
+
+// toPromptEvent is a function to convert OpenAI stream event into prompt event
+// It will return nil for event that is not relayed to client
+func toPromptEvent(threadID string, event *connector.Event) (*response.PromptEvent, error) {
+	switch {
+	case event.Type == connector.EventDone:
+		return &response.PromptEvent{Type: response.PromptEventDone, ThreadID: threadID}, nil
+	case event.Type == connector.EventMessageDelta:
+		delta, err := event.MessageDelta()
+		if err != nil {
+			return nil, err
+		}
+
+		return &response.PromptEvent{Type: response.PromptEventDelta, ThreadID: threadID, Text: delta.Text()}, nil
+	case event.Type == connector.EventMessageCompleted:
+		message, err := event.Message()
+		if err != nil {
+			return nil, err
+		}
+
+		resolveMessage(threadID, message)
+
+		return &response.PromptEvent{Type: response.PromptEventMessage, ThreadID: threadID, RunID: message.RunID, Message: message}, nil
+	case event.IsRun():
+		run, err := event.Run()
+		if err != nil {
+			return nil, err
+		}
+
+		return &response.PromptEvent{Type: response.PromptEventStatus, ThreadID: threadID, RunID: run.ID, Status: run.Status}, nil
+	default:
+		return nil, nil
+	}
 }
@@ -2,23 +2,34 @@ package usecases_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
 	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestPromptUsecase_SendPrompt(t *testing.T) {
 	type args struct {
-		ctx     context.Context
-		message string
+		ctx    context.Context
+		prompt *request.Prompt
 	}
 
 	type test struct {
 		fields  promptFields
 		args    args
-		want    *repository.Message
+		want    *response.Prompt
 		wantErr error
 	}
 
@@ -27,16 +38,28 @@ func TestPromptUsecase_SendPrompt(t *testing.T) {
 			ctx := context.Background()
 
 			args := args{
-				ctx:     ctx,
-				message: "Hello World",
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World"},
 			}
 
 			mockConnector := connector.NewGoMockConnector(ctrl)
 
-			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIThread{
-				FirstID: "thread-1",
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Thread{
+				ID: "thread-1",
 			})
 
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Create(args.ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, conversation *repository.Conversation) error {
+					assert.Equal(t, "thread-1", conversation.ThreadID)
+					assert.Equal(t, "Hello World", conversation.Title)
+
+					conversation.ID = "conversation-1"
+
+					return nil
+				},
+			)
+
 			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
 				ID: "message-1",
 			})
@@ -56,10 +79,12 @@ func TestPromptUsecase_SendPrompt(t *testing.T) {
 					Object:    "message",
 					CreatedAt: 1234567890,
 					ThreadID:  "thread-1",
+					Role:      "assistant",
+					RunID:     "run-1",
 					Content: []repository.ContentMessage{
 						{
 							Type: "text",
-							Text: repository.TextContent{
+							Text: &repository.TextContent{
 								Value: "Hello World",
 							},
 						},
@@ -71,19 +96,2116 @@ func TestPromptUsecase_SendPrompt(t *testing.T) {
 				Data: expected,
 			})
 
+			mockConversationRepository.EXPECT().Modify(args.ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+
 			return test{
 				fields: promptFields{
-					connector: mockConnector,
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-1",
+					ThreadID:       "thread-1",
+					RunID:          "run-1",
+					Messages:       expected,
 				},
-				args:    args,
-				want:    &expected[0],
 				wantErr: nil,
 			}
 		},
-	}
+		"Given request of Send Prompt, When run creates message with annotations and image, Return footnotes and download links": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
 
-	for name, testFn := range tests {
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Plot the sales", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, change func(conversation *repository.Conversation)) (*repository.Conversation, error) {
+					stored := *conversation
+					change(&stored)
+					assert.Equal(t, []string{"file-2", "file-1", "file-3"}, stored.FileIDs)
+
+					return &stored, nil
+				})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			citation := repository.Annotation{
+				Type:         repository.AnnotationTypeFileCitation,
+				Text:         "【7†source】",
+				FileCitation: &repository.FileCitation{FileID: "file-1", Quote: "sales grew"},
+			}
+			filePath := repository.Annotation{
+				Type:     repository.AnnotationTypeFilePath,
+				Text:     "sandbox:/mnt/data/sales.csv",
+				FilePath: &repository.FilePath{FileID: "file-3"},
+			}
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: []repository.Message{
+					{
+						ID:    "message-2",
+						Role:  "assistant",
+						RunID: "run-1",
+						Content: []repository.ContentMessage{
+							{
+								Type:      repository.ContentTypeImageFile,
+								ImageFile: &repository.ImageFileContent{FileID: "file-2"},
+							},
+							{
+								Type: repository.ContentTypeText,
+								Text: &repository.TextContent{
+									Value:       "Sales grew【7†source】, again【7†source】. [Download](sandbox:/mnt/data/sales.csv)",
+									Annotations: []repository.Annotation{citation, citation, filePath},
+								},
+							},
+						},
+					},
+				},
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages: []repository.Message{
+						{
+							ID:    "message-2",
+							Role:  "assistant",
+							RunID: "run-1",
+							Content: []repository.ContentMessage{
+								{
+									Type: repository.ContentTypeImageFile,
+									ImageFile: &repository.ImageFileContent{
+										FileID: "file-2",
+										URL:    "/api/v1/threads/thread-2/files/file-2/content",
+									},
+								},
+								{
+									Type: repository.ContentTypeText,
+									Text: &repository.TextContent{
+										Value:       "Sales grew[1], again[1]. [Download](/api/v1/threads/thread-2/files/file-3/content)",
+										Annotations: []repository.Annotation{citation, citation, filePath},
+										Footnotes: []repository.Footnote{
+											{Number: 1, FileID: "file-1", Quote: "sales grew", URL: "/api/v1/threads/thread-2/files/file-1/content"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt with thread ID, When repository executed successfully, Return response in the same thread": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2", OwnerID: "user-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				OwnerID:  "user-1",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			expected := []repository.Message{
+				{
+					ID:       "message-2",
+					ThreadID: "thread-2",
+					Role:     "assistant",
+					RunID:    "run-1",
+				},
+			}
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: expected,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages:       expected,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt, When run creates several messages in several pages, Return assistant messages of the run in order": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			expected := []repository.Message{
+				{ID: "message-2", ThreadID: "thread-2", Role: "assistant", RunID: "run-1"},
+				{ID: "message-3", ThreadID: "thread-2", Role: "assistant", RunID: "run-1"},
+				{ID: "message-4", ThreadID: "thread-2", Role: "assistant", RunID: "run-1"},
+			}
+
+			mockConnector.EXPECT().Send(args.ctx, &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/threads/thread-2/messages?limit=100&order=asc&run_id=run-1",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: []repository.Message{
+					expected[0],
+					{ID: "message-5", ThreadID: "thread-2", Role: "assistant", RunID: "run-2"},
+					expected[1],
+				},
+				LastID:  "message-3",
+				HasMore: true,
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/threads/thread-2/messages?after=message-3&limit=100&order=asc&run_id=run-1",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data:   expected[2:],
+				LastID: "message-4",
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages:       expected,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt, When run creates no message, Return empty messages": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages:       []repository.Message{},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt in conversation of other assistant, When run is created, Return response of the conversation assistant": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx: ctx,
+				prompt: &request.Prompt{
+					Message:                "Hello again",
+					ThreadID:               "thread-2",
+					AdditionalInstructions: "Answer in Bahasa",
+					Metadata:               map[string]string{"channel": "web"},
+				},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:          "conversation-2",
+				ThreadID:    "thread-2",
+				AssistantID: "asst-support",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+					assert.Equal(t, "/threads/thread-2/runs", requestOption.URL)
+
+					body, err := io.ReadAll(requestOption.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, `{
+						"assistant_id": "asst-support",
+						"additional_instructions": "Answer in Bahasa",
+						"metadata": {"channel": "web"}
+					}`, string(body))
+
+					*(result.(**connector.OpenAIRun)) = &connector.OpenAIRun{ID: "run-1"}
+
+					return nil
+				},
+			)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					runPolicy: usecases.NewRunPolicy(usecases.RunPolicyConfig{
+						DefaultAssistantID: "asst-default",
+						AllowInstructions:  true,
+					}, nil, nil),
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages:       []repository.Message{},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt with model out of allow-list, When run options are validated, Return not allowed error": func(t *testing.T, ctrl *gomock.Controller) test {
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: repository.NewGoMockConversationRepository(ctrl),
+				},
+				args: args{
+					ctx:    context.Background(),
+					prompt: &request.Prompt{Message: "Hello", Model: "gpt-4"},
+				},
+				want:    nil,
+				wantErr: usecases.ErrRunOptionNotAllowed,
+			}
+		},
+		"Given request of Send Prompt, When run requires action, Return response after tool outputs are submitted": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "What time is it?", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(args.ctx, conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			toolRegistry := usecases.NewToolRegistry()
+			require.NoError(t, toolRegistry.Register(usecases.Tool{
+				Name: "get_time",
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					return "10:00", nil
+				},
+			}))
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
 
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusRequiresAction,
+				RequiredAction: &connector.RequiredAction{
+					Type: connector.OpenAIRequiredActionSubmitToolOutputs,
+					SubmitToolOutputs: connector.SubmitToolOutputs{
+						ToolCalls: []connector.ToolCall{
+							{ID: "call-1", Type: "function", Function: connector.ToolCallFunction{Name: "get_time", Arguments: "{}"}},
+						},
+					},
+				},
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, requestOption *connector.RequestOption, result any) error {
+					assert.Equal(t, "/threads/thread-2/runs/run-1/submit_tool_outputs", requestOption.URL)
+
+					var body connector.RequestToolOutputs
+					require.NoError(t, json.NewDecoder(requestOption.Body).Decode(&body))
+					assert.Equal(t, []connector.ToolOutput{{ToolCallID: "call-1", Output: "10:00"}}, body.ToolOutputs)
+
+					*(result.(**connector.OpenAIRun)) = &connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusQueued}
+
+					return nil
+				},
+			)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			expected := []repository.Message{
+				{
+					ID:       "message-2",
+					ThreadID: "thread-2",
+					Role:     "assistant",
+					RunID:    "run-1",
+				},
+			}
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: expected,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					toolRegistry:           toolRegistry,
+				},
+				args: args,
+				want: &response.Prompt{
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					RunID:          "run-1",
+					Messages:       expected,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Send Prompt, When run is failed, Return run failed error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusFailed,
+				LastError: &connector.RunLastError{
+					Code:    "server_error",
+					Message: "Sorry, something went wrong.",
+				},
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: connector.ErrRunFailed,
+			}
+		},
+		"Given request of Send Prompt, When run is not finished before max wait, Return timeout error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusInProgress,
+			}).MinTimes(1)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-2/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					pollStrategy: usecases.NewBackoffPollStrategy(usecases.PollConfig{
+						InitialInterval: 10 * time.Millisecond,
+						MaxWait:         25 * time.Millisecond,
+					}),
+				},
+				args:    args,
+				want:    nil,
+				wantErr: usecases.ErrRunPollTimeout,
+			}
+		},
+		"Given request of Send Prompt, When run keeps requiring action until max wait, Return timeout error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "What time is it?", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}, nil)
+
+			toolRegistry := usecases.NewToolRegistry()
+			require.NoError(t, toolRegistry.Register(usecases.Tool{
+				Name: "get_time",
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					return "10:00", nil
+				},
+			}))
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			requestURL := func(url string) gomock.Matcher {
+				return gomock.Cond(func(x any) bool {
+					return x.(*connector.RequestOption).URL == url
+				})
+			}
+
+			mockConnector.EXPECT().Send(args.ctx, requestURL("/threads/thread-2/runs/run-1"), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusRequiresAction,
+				RequiredAction: &connector.RequiredAction{
+					Type: connector.OpenAIRequiredActionSubmitToolOutputs,
+					SubmitToolOutputs: connector.SubmitToolOutputs{
+						ToolCalls: []connector.ToolCall{
+							{ID: "call-1", Type: "function", Function: connector.ToolCallFunction{Name: "get_time", Arguments: "{}"}},
+						},
+					},
+				},
+			}).MinTimes(1).MaxTimes(3)
+
+			mockConnector.EXPECT().Send(args.ctx, requestURL("/threads/thread-2/runs/run-1/submit_tool_outputs"), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusQueued,
+			}).MinTimes(1).MaxTimes(3)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-2/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					toolRegistry:           toolRegistry,
+					pollStrategy: usecases.NewBackoffPollStrategy(usecases.PollConfig{
+						InitialInterval: 10 * time.Millisecond,
+						MaxWait:         25 * time.Millisecond,
+					}),
+				},
+				args:    args,
+				want:    nil,
+				wantErr: usecases.ErrRunPollTimeout,
+			}
+		},
+		"Given request of Send Prompt, When context is cancelled while polling, Return context error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ *connector.RequestOption, result any) error {
+					*(result.(**connector.OpenAIRun)) = &connector.OpenAIRun{ID: "run-1", Status: connector.OpenAIStatusQueued}
+					cancel()
+
+					return nil
+				},
+			)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-2/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					pollStrategy:           usecases.NewBackoffPollStrategy(usecases.PollConfig{InitialInterval: time.Hour}),
+				},
+				args:    args,
+				want:    nil,
+				wantErr: context.Canceled,
+			}
+		},
+		"Given request of Send Prompt, When polling run fails, Return error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				ThreadID: "thread-2",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			apiErr := &connector.APIError{HTTPStatus: http.StatusBadGateway, Message: "Bad gateway."}
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(apiErr)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-2/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: apiErr,
+			}
+		},
+		"Given request of Send Prompt with conversation of other owner, When conversation is found, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ConversationID: "conversation-3", OwnerID: "user-1"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Get(args.ctx, "conversation-3").Return(&repository.Conversation{
+				ID:       "conversation-3",
+				OwnerID:  "user-2",
+				ThreadID: "thread-3",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			got, err := sut.SendPrompt(tt.args.ctx, tt.args.prompt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptUsecase_SendPromptAttachments(t *testing.T) {
+	type test struct {
+		fields  promptFields
+		prompt  *request.Prompt
+		want    *response.Prompt
+		wantErr error
+	}
+
+	// isRequest is a function to match request of connector by its method and URL
+	isRequest := func(method, url string) gomock.Matcher {
+		return gomock.Cond(func(x any) bool {
+			option := x.(*connector.RequestOption)
+			return option.Method == method && option.URL == url
+		})
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given prompt with file of the conversation and inline file, When repository executed successfully, Return response with attachments": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+				FileIDs:  []string{"file-1"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(ctx, "thread-1").Return(conversation, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			expected := []repository.Message{{ID: "message-2", Role: "assistant", RunID: "run-1"}}
+
+			gomock.InOrder(
+				mockConnector.EXPECT().Send(ctx, isRequest(http.MethodGet, "/files/file-1"), gomock.Any()).Return(nil).SetArg(2, &repository.File{
+					ID:       "file-1",
+					Filename: "report.pdf",
+					Bytes:    2048,
+				}),
+				mockConnector.EXPECT().Send(ctx, isRequest(http.MethodPost, "/files"), gomock.Any()).DoAndReturn(
+					func(_ context.Context, option *connector.RequestOption, result any) error {
+						assert.Equal(t, "assistants", option.Multipart[0].Value)
+						assert.Equal(t, "notes.txt", option.Multipart[1].Filename)
+						assert.Equal(t, int64(5), option.Multipart[1].Size)
+
+						*(result.(**repository.File)) = &repository.File{ID: "file-2", Filename: "notes.txt", Bytes: 5}
+
+						return nil
+					}),
+				mockConversationRepository.EXPECT().Modify(ctx, conversation.ID, gomock.Any()).DoAndReturn(
+					func(_ context.Context, id string, change func(*repository.Conversation)) (*repository.Conversation, error) {
+						// Title changed while the prompt is in flight is kept
+						stored := &repository.Conversation{ID: id, Title: "Renamed", FileIDs: []string{"file-1"}}
+						change(stored)
+
+						assert.Equal(t, "Renamed", stored.Title)
+						assert.Equal(t, []string{"file-1", "file-2"}, stored.FileIDs)
+
+						return stored, nil
+					}),
+				mockConnector.EXPECT().Send(ctx, isRequest(http.MethodPost, "/threads/thread-1/messages"), gomock.Any()).DoAndReturn(
+					func(_ context.Context, option *connector.RequestOption, result any) error {
+						var message connector.RequestMessage
+						require.NoError(t, json.NewDecoder(option.Body).Decode(&message))
+						assert.Equal(t, []string{"file-1", "file-2"}, message.FileIDs)
+
+						*(result.(**repository.Message)) = &repository.Message{ID: "message-1"}
+
+						return nil
+					}),
+				mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+					ID: "run-1",
+				}),
+				mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+					ID:     "run-1",
+					Status: "completed",
+				}),
+				mockConnector.EXPECT().Send(ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+					Data: expected,
+				}),
+				mockConversationRepository.EXPECT().Modify(ctx, conversation.ID, gomock.Any()).Return(conversation, nil),
+			)
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				prompt: &request.Prompt{
+					Message:  "Summarize these documents",
+					ThreadID: "thread-1",
+					OwnerID:  "user-1",
+					FileIDs:  []string{"file-1"},
+					File:     &request.PromptFile{Filename: "notes.txt", ContentType: "text/plain", Data: []byte("hello")},
+				},
+				want: &response.Prompt{
+					ConversationID: "conversation-1",
+					ThreadID:       "thread-1",
+					RunID:          "run-1",
+					Messages:       expected,
+					Attachments: []response.Attachment{
+						{FileID: "file-1", Filename: "report.pdf", Bytes: 2048},
+						{FileID: "file-2", Filename: "notes.txt", Bytes: 5, Uploaded: true},
+					},
+				},
+			}
+		},
+		"Given prompt with file of other conversation, When Send Prompt, Return not allowed error before the file is read": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				prompt: &request.Prompt{
+					Message:  "Summarize this document",
+					ThreadID: "thread-1",
+					OwnerID:  "user-1",
+					FileIDs:  []string{"file-other"},
+				},
+				wantErr: usecases.ErrAttachmentNotAllowed,
+			}
+		},
+		"Given prompt with too many files, When Send Prompt, Return invalid attachment error before thread is created": func(t *testing.T, ctrl *gomock.Controller) test {
+			fileIDs := make([]string, 10)
+			for i := range fileIDs {
+				fileIDs[i] = fmt.Sprintf("file-%d", i)
+			}
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: repository.NewGoMockConversationRepository(ctrl),
+				},
+				prompt: &request.Prompt{
+					Message: "Summarize these documents",
+					FileIDs: fileIDs,
+					File:    &request.PromptFile{Filename: "notes.txt", Data: []byte("hello")},
+				},
+				wantErr: usecases.ErrAttachmentInvalid,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			got, err := sut.SendPrompt(context.Background(), tt.prompt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptUsecase_StreamPrompt(t *testing.T) {
+	type args struct {
+		ctx     context.Context
+		prompt  *request.Prompt
+		sendErr error
+	}
+
+	type test struct {
+		fields     promptFields
+		args       args
+		want       []*response.PromptEvent
+		wantErr    error
+		wantErrMsg string
+	}
+
+	errDisconnected := errors.New("client disconnected")
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Stream Prompt, When run is streamed successfully, Return relayed events": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockStream := connector.NewGoMockEventStream(ctrl)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Thread{
+				ID: "thread-1",
+			})
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Create(args.ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, conversation *repository.Conversation) error {
+					assert.Equal(t, "thread-1", conversation.ThreadID)
+					assert.Equal(t, "Hello World", conversation.Title)
+
+					conversation.ID = "conversation-1"
+
+					return nil
+				},
+			)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Stream(args.ctx, gomock.Any()).Return(mockStream, nil)
+
+			gomock.InOrder(
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunCreated,
+					Data: []byte(`{"id":"run-1","status":"queued"}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunStepCreated,
+					Data: []byte(`{"id":"step-1"}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventMessageDelta,
+					Data: []byte(`{"id":"message-2","delta":{"content":[{"index":0,"type":"text","text":{"value":"Hi"}}]}}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunCompleted,
+					Data: []byte(`{"id":"run-1","status":"completed"}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(&connector.Event{Type: connector.EventDone}, nil),
+				mockStream.EXPECT().Recv().Return(nil, io.EOF),
+			)
+			mockStream.EXPECT().Close().Return(nil)
+
+			mockConversationRepository.EXPECT().Modify(args.ctx, gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: []*response.PromptEvent{
+					{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "queued"},
+					{Type: response.PromptEventDelta, ConversationID: "conversation-1", ThreadID: "thread-1", Text: "Hi"},
+					{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "completed"},
+					{Type: response.PromptEventDone, ConversationID: "conversation-1", ThreadID: "thread-1"},
+				},
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Stream Prompt, When client is disconnected, Return error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:     ctx,
+				prompt:  &request.Prompt{Message: "Hello World"},
+				sendErr: errDisconnected,
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockStream := connector.NewGoMockEventStream(ctrl)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Thread{
+				ID: "thread-1",
+			})
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Create(args.ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, conversation *repository.Conversation) error {
+					assert.Equal(t, "thread-1", conversation.ThreadID)
+					assert.Equal(t, "Hello World", conversation.Title)
+
+					conversation.ID = "conversation-1"
+
+					return nil
+				},
+			)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Stream(args.ctx, gomock.Any()).Return(mockStream, nil)
+
+			mockStream.EXPECT().Recv().Return(&connector.Event{
+				Type: connector.EventRunCreated,
+				Data: []byte(`{"id":"run-1","status":"queued"}`),
+			}, nil)
+			mockStream.EXPECT().Close().Return(nil)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-1/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    []*response.PromptEvent{{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "queued"}},
+				wantErr: errDisconnected,
+			}
+		},
+		"Given valid request of Stream Prompt, When event cannot be decoded, Return error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World", ThreadID: "thread-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockStream := connector.NewGoMockEventStream(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Stream(args.ctx, gomock.Any()).Return(mockStream, nil)
+
+			gomock.InOrder(
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunCreated,
+					Data: []byte(`{"id":"run-1","status":"queued"}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventMessageCompleted,
+					Data: []byte(`{"id":"message-2",`),
+				}, nil),
+			)
+			mockStream.EXPECT().Close().Return(nil)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-1/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:       args,
+				want:       []*response.PromptEvent{{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "queued"}},
+				wantErrMsg: "unexpected end of JSON input",
+			}
+		},
+		"Given valid request of Stream Prompt, When run is streamed longer than max wait, Return deadline error and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World", ThreadID: "thread-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockStream := connector.NewGoMockEventStream(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			var streamCtx context.Context
+			mockConnector.EXPECT().Stream(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, _ *connector.RequestOption) (connector.EventStream, error) {
+					_, ok := ctx.Deadline()
+					assert.True(t, ok)
+
+					streamCtx = ctx
+
+					return mockStream, nil
+				},
+			)
+
+			gomock.InOrder(
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunCreated,
+					Data: []byte(`{"id":"run-1","status":"queued"}`),
+				}, nil),
+				// The run never ends, so the stream is read until the deadline
+				mockStream.EXPECT().Recv().DoAndReturn(func() (*connector.Event, error) {
+					<-streamCtx.Done()
+					return nil, streamCtx.Err()
+				}),
+			)
+			mockStream.EXPECT().Close().Return(nil)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-1/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					pollStrategy:           usecases.NewBackoffPollStrategy(usecases.PollConfig{MaxWait: 10 * time.Millisecond}),
+				},
+				args:    args,
+				want:    []*response.PromptEvent{{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "queued"}},
+				wantErr: context.DeadlineExceeded,
+			}
+		},
+		"Given request of Stream Prompt, When thread belongs to other owner, Return not found error before streaming": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World", ThreadID: "thread-1", OwnerID: "user-2"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+		"Given valid request of Stream Prompt, When stream is closed before the run is done, Return unexpected EOF and cancel run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello World", ThreadID: "thread-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockStream := connector.NewGoMockEventStream(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Stream(args.ctx, gomock.Any()).Return(mockStream, nil)
+
+			gomock.InOrder(
+				mockStream.EXPECT().Recv().Return(&connector.Event{
+					Type: connector.EventRunCreated,
+					Data: []byte(`{"id":"run-1","status":"queued"}`),
+				}, nil),
+				mockStream.EXPECT().Recv().Return(nil, io.EOF),
+			)
+			mockStream.EXPECT().Close().Return(nil)
+
+			mockConnector.EXPECT().Send(gomock.Any(), &connector.RequestOption{
+				Method: http.MethodPost,
+				URL:    "/threads/thread-1/runs/run-1/cancel",
+				CustomHeader: map[string]string{
+					connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+				},
+			}, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCancelling,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    []*response.PromptEvent{{Type: response.PromptEventStatus, ConversationID: "conversation-1", ThreadID: "thread-1", RunID: "run-1", Status: "queued"}},
+				wantErr: io.ErrUnexpectedEOF,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			var got []*response.PromptEvent
+
+			prepared, err := sut.PreparePrompt(tt.args.ctx, tt.args.prompt)
+			if err == nil {
+				err = sut.StreamPrompt(tt.args.ctx, prepared, func(event *response.PromptEvent) error {
+					got = append(got, event)
+					return tt.args.sendErr
+				})
+			}
+
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantErrMsg != "":
+				assert.ErrorContains(t, err, tt.wantErrMsg)
+			default:
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptUsecase_SubmitPrompt(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		prompt *request.Prompt
+	}
+
+	type test struct {
+		fields  promptFields
+		args    args
+		want    *response.Run
+		wantErr error
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Submit Prompt, When run is completed in background, Return completed run": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2", OwnerID: "user-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			conversation := &repository.Conversation{
+				ID:       "conversation-2",
+				OwnerID:  "user-1",
+				ThreadID: "thread-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Modify(gomock.Any(), conversation.ID, gomock.Any()).Return(conversation, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "completed",
+			})
+
+			expected := []repository.Message{
+				{
+					ID:       "message-2",
+					ThreadID: "thread-2",
+					Role:     "assistant",
+					RunID:    "run-1",
+				},
+			}
+
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: expected,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Run{
+					ID:             "run-1",
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					Status:         "completed",
+					Messages:       expected,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Submit Prompt, When run is failed in background, Return failed run with error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2", OwnerID: "user-1"},
+			}
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				OwnerID:  "user-1",
+				ThreadID: "thread-2",
+			}, nil)
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+				ID: "message-1",
+			})
+
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID: "run-1",
+			})
+
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: "failed",
+				LastError: &connector.RunLastError{
+					Code:    "server_error",
+					Message: "something went wrong",
+				},
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Run{
+					ID:             "run-1",
+					ConversationID: "conversation-2",
+					ThreadID:       "thread-2",
+					Status:         "failed",
+					Error:          "run failed: run_id=run-1 code=server_error: something went wrong",
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Submit Prompt with conversation of other owner, When conversation is found, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			ctx := context.Background()
+
+			args := args{
+				ctx:    ctx,
+				prompt: &request.Prompt{Message: "Hello again", ThreadID: "thread-2", OwnerID: "user-2"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-2").Return(&repository.Conversation{
+				ID:       "conversation-2",
+				OwnerID:  "user-1",
+				ThreadID: "thread-2",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			submitted, err := sut.SubmitPrompt(tt.args.ctx, tt.args.prompt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, submitted)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want.ID, submitted.ID)
+
+			var got *response.Run
+
+			require.Eventually(t, func() bool {
+				got, err = sut.GetRun(tt.args.ctx, submitted.ID, tt.args.prompt.OwnerID)
+				require.NoError(t, err)
+
+				return got.FinishedAt > 0
+			}, time.Second, time.Millisecond)
+
+			_, err = sut.GetRun(tt.args.ctx, submitted.ID, "other-user")
+			assert.ErrorIs(t, err, usecases.ErrRunNotFound)
+
+			got.CreatedAt, got.FinishedAt = 0, 0
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptUsecase_SubmitPromptLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Every slot of background run is taken by run that polls again only after an hour
+	const runs = 32
+
+	ctx := context.Background()
+
+	mockConnector := connector.NewGoMockConnector(ctrl)
+
+	mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+	mockConversationRepository.EXPECT().GetByThreadID(ctx, "thread-1").Return(&repository.Conversation{
+		ID:       "conversation-1",
+		OwnerID:  "user-1",
+		ThreadID: "thread-1",
+	}, nil).Times(runs)
+
+	requestOption := func(method, url string) gomock.Matcher {
+		return gomock.Cond(func(x any) bool {
+			requestOption := x.(*connector.RequestOption)
+			return requestOption.Method == method && requestOption.URL == url
+		})
+	}
+
+	mockConnector.EXPECT().Send(ctx, requestOption(http.MethodPost, "/threads/thread-1/messages"), gomock.Any()).Return(nil).SetArg(2, &repository.Message{
+		ID: "message-1",
+	}).Times(runs)
+
+	runID := 0
+	mockConnector.EXPECT().Send(ctx, requestOption(http.MethodPost, "/threads/thread-1/runs"), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *connector.RequestOption, result any) error {
+			runID++
+			*(result.(**connector.OpenAIRun)) = &connector.OpenAIRun{ID: fmt.Sprintf("run-%d", runID)}
+
+			return nil
+		},
+	).Times(runs)
+
+	var polled sync.WaitGroup
+	polled.Add(runs)
+
+	mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *connector.RequestOption, result any) error {
+			defer polled.Done()
+			*(result.(**connector.OpenAIRun)) = &connector.OpenAIRun{Status: connector.OpenAIStatusInProgress}
+
+			return nil
+		},
+	).Times(runs)
+
+	sut := promptSut(promptFields{
+		connector:              mockConnector,
+		conversationRepository: mockConversationRepository,
+		pollStrategy:           usecases.NewBackoffPollStrategy(usecases.PollConfig{InitialInterval: time.Hour}),
+	})
+
+	prompt := &request.Prompt{Message: "Hello again", ThreadID: "thread-1", OwnerID: "user-1"}
+
+	for i := 0; i < runs; i++ {
+		_, err := sut.SubmitPrompt(ctx, prompt)
+		require.NoError(t, err)
+	}
+
+	got, err := sut.SubmitPrompt(ctx, prompt)
+	assert.ErrorIs(t, err, usecases.ErrTooManyRuns)
+	assert.Nil(t, got)
+
+	// Background runs must poll before the controller is finished
+	polled.Wait()
+}
+
+func TestPromptUsecase_PreparePromptProfile(t *testing.T) {
+	type test struct {
+		fields  promptFields
+		prompts []*request.Prompt
+		wantErr error
+	}
+
+	profiles, err := usecases.NewProfileRegistry([]usecases.Profile{
+		{
+			Name:        "limited",
+			AssistantID: "asst-limited",
+			RateLimit:   usecases.ProfileRateLimit{RequestsPerMinute: 1, Burst: 1},
+		},
+		{
+			Name:        "support",
+			AssistantID: "asst-support",
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	runPolicy := func() usecases.RunPolicy {
+		return usecases.NewRunPolicy(usecases.RunPolicyConfig{DefaultAssistantID: "asst-default"}, nil, profiles)
+	}
+
+	conversation := &repository.Conversation{
+		ID:          "conversation-1",
+		OwnerID:     "user-1",
+		ThreadID:    "thread-1",
+		AssistantID: "asst-limited",
+		Profile:     "limited",
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given prompt with profile starting conversation, When conversation is created, Return no error and store the profile": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).SetArg(2, &repository.Thread{
+				ID: "thread-2",
+			})
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, conversation *repository.Conversation) error {
+					assert.Equal(t, "asst-support", conversation.AssistantID)
+					assert.Equal(t, "support", conversation.Profile)
+
+					return nil
+				},
+			)
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+					runPolicy:              runPolicy(),
+				},
+				prompts: []*request.Prompt{
+					{Message: "Hello World", OwnerID: "user-1", Profile: "support"},
+				},
+			}
+		},
+		"Given prompts without profile continuing conversation of rate limited profile, When the profile is reapplied, Return rate limited error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().Get(gomock.Any(), "conversation-1").Return(conversation, nil).Times(2)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+					runPolicy:              runPolicy(),
+				},
+				prompts: []*request.Prompt{
+					{Message: "Hello again", OwnerID: "user-1", ConversationID: "conversation-1"},
+					{Message: "Hello again", OwnerID: "user-1", ConversationID: "conversation-1"},
+				},
+				wantErr: usecases.ErrProfileRateLimited,
+			}
+		},
+		"Given prompt with other profile continuing conversation of profile, When conversation is found, Return run option not allowed error": func(t *testing.T, ctrl *gomock.Controller) test {
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(gomock.Any(), "thread-1").Return(conversation, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+					runPolicy:              runPolicy(),
+				},
+				prompts: []*request.Prompt{
+					{Message: "Hello again", OwnerID: "user-1", ThreadID: "thread-1", Profile: "support"},
+				},
+				wantErr: usecases.ErrRunOptionNotAllowed,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			var err error
+			for _, prompt := range tt.prompts {
+				if _, err = sut.PreparePrompt(context.Background(), prompt); err != nil {
+					break
+				}
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPromptUsecase_CancelRun(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		threadID string
+		runID    string
+		ownerID  string
+	}
+
+	type test struct {
+		fields  promptFields
+		args    args
+		want    *response.Run
+		wantErr error
+	}
+
+	cancelRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    "/threads/thread-1/runs/run-1/cancel",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	getRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/threads/thread-1/runs/run-1",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	cancelledAt := int64(1234567899)
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Cancel Run, When run is cancelled, Return cancelled run": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				runID:    "run-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			gomock.InOrder(
+				mockConnector.EXPECT().Send(args.ctx, cancelRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+					ID:        "run-1",
+					CreatedAt: 1234567890,
+					Status:    connector.OpenAIStatusCancelling,
+				}),
+				mockConnector.EXPECT().Send(args.ctx, getRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+					ID:        "run-1",
+					CreatedAt: 1234567890,
+					Status:    connector.OpenAIStatusCancelling,
+				}),
+				mockConnector.EXPECT().Send(args.ctx, getRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+					ID:          "run-1",
+					CreatedAt:   1234567890,
+					Status:      connector.OpenAIStatusCancelled,
+					CancelledAt: &cancelledAt,
+				}),
+			)
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Run{
+					ID:             "run-1",
+					ConversationID: "conversation-1",
+					ThreadID:       "thread-1",
+					Status:         connector.OpenAIStatusCancelled,
+					CreatedAt:      1234567890,
+					FinishedAt:     cancelledAt,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Cancel Run, When run is already completed, Return not cancellable error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				runID:    "run-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, cancelRequestOption, gomock.Any()).Return(&connector.APIError{
+				HTTPStatus: http.StatusBadRequest,
+				Message:    "Cannot cancel run with status 'completed'.",
+			})
+			mockConnector.EXPECT().Send(args.ctx, getRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIRun{
+				ID:     "run-1",
+				Status: connector.OpenAIStatusCompleted,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: usecases.ErrRunNotCancellable,
+			}
+		},
+		"Given request of Cancel Run with thread of other owner, When conversation is found, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				runID:    "run-1",
+				ownerID:  "user-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := promptSut(tt.fields)
+
+			got, err := sut.CancelRun(tt.args.ctx, tt.args.threadID, tt.args.runID, tt.args.ownerID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptUsecase_GetListMessage(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		option *request.ListMessage
+	}
+
+	type test struct {
+		fields  promptFields
+		args    args
+		want    *response.List[response.Message]
+		wantErr error
+	}
+
+	listRequestOption := &connector.RequestOption{
+		Method: http.MethodGet,
+		URL:    "/threads/thread-1/messages?before=message-9&limit=2&order=asc",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get List Message, When thread belongs to the owner, Return normalized messages": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+				option: &request.ListMessage{
+					ListOption: request.ListOption{Limit: 2, Before: "message-9", Order: "asc"},
+					ThreadID:   "thread-1",
+					OwnerID:    "user-1",
+				},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, listRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIMessage{
+				Data: []repository.Message{
+					{
+						ID:        "message-1",
+						CreatedAt: 1234567890,
+						Role:      "user",
+						FileIDS:   []string{"file-1"},
+						Content: []repository.ContentMessage{
+							{Type: repository.ContentTypeText, Text: &repository.TextContent{Value: "Plot the sales"}},
+						},
+					},
+					{
+						ID:        "message-2",
+						CreatedAt: 1234567891,
+						Role:      "assistant",
+						RunID:     "run-1",
+						Content: []repository.ContentMessage{
+							{Type: repository.ContentTypeImageFile, ImageFile: &repository.ImageFileContent{FileID: "file-2"}},
+							{
+								Type: repository.ContentTypeText,
+								Text: &repository.TextContent{
+									Value: "Sales grew【7†source】",
+									Annotations: []repository.Annotation{
+										{
+											Type:         repository.AnnotationTypeFileCitation,
+											Text:         "【7†source】",
+											FileCitation: &repository.FileCitation{FileID: "file-1", Quote: "sales grew"},
+										},
+									},
+								},
+							},
+							{Type: repository.ContentTypeText, Text: &repository.TextContent{Value: "Anything else?"}},
+						},
+					},
+				},
+				HasMore: true,
+			})
+
+			return test{
+				fields: promptFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.List[response.Message]{
+					Data: []response.Message{
+						{
+							ID:          "message-1",
+							Role:        "user",
+							Text:        "Plot the sales",
+							Attachments: []response.MessageFile{{FileID: "file-1", URL: "/api/v1/threads/thread-1/files/file-1/content"}},
+							CreatedAt:   1234567890,
+						},
+						{
+							ID:   "message-2",
+							Role: "assistant",
+							Text: "Sales grew[1]\n\nAnything else?",
+							Footnotes: []repository.Footnote{
+								{Number: 1, FileID: "file-1", Quote: "sales grew", URL: "/api/v1/threads/thread-1/files/file-1/content"},
+							},
+							Images:    []response.MessageFile{{FileID: "file-2", URL: "/api/v1/threads/thread-1/files/file-2/content"}},
+							RunID:     "run-1",
+							CreatedAt: 1234567891,
+						},
+					},
+					HasMore: true,
+					FirstID: "message-1",
+					LastID:  "message-2",
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Get List Message, When thread belongs to other owner, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:    context.Background(),
+				option: &request.ListMessage{ThreadID: "thread-1", OwnerID: "user-2"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(&repository.Conversation{
+				ID:       "conversation-1",
+				OwnerID:  "user-1",
+				ThreadID: "thread-1",
+			}, nil)
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+		"Given request of Get List Message with invalid limit, When it is validated, Return list option invalid error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx: context.Background(),
+				option: &request.ListMessage{
+					ListOption: request.ListOption{Limit: 101},
+					ThreadID:   "thread-1",
+					OwnerID:    "user-1",
+				},
+			}
+
+			return test{
+				fields: promptFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: repository.NewGoMockConversationRepository(ctrl),
+				},
+				args:    args,
+				want:    nil,
+				wantErr: usecases.ErrListOptionInvalid,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
 		t.Run(name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
@@ -92,7 +2214,7 @@ func TestPromptUsecase_SendPrompt(t *testing.T) {
 
 			sut := promptSut(tt.fields)
 
-			got, err := sut.SendPrompt(tt.args.ctx, tt.args.message)
+			got, err := sut.GetListMessage(tt.args.ctx, tt.args.option)
 			if tt.wantErr != nil {
 				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
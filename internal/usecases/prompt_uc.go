@@ -2,21 +2,60 @@ package usecases
 
 import (
 	"context"
+	"errors"
 
 	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 )
 
+var ErrRunNotCancellable = errors.New("run is already finished")
+
 type PromptUsecase interface {
-	SendPrompt(ctx context.Context, message string) (*repository.Message, error)
+	SendPrompt(ctx context.Context, prompt *request.Prompt) (*response.Prompt, error)
+	PreparePrompt(ctx context.Context, prompt *request.Prompt) (*PreparedPrompt, error)
+	StreamPrompt(ctx context.Context, prepared *PreparedPrompt, send func(event *response.PromptEvent) error) error
+	SubmitPrompt(ctx context.Context, prompt *request.Prompt) (*response.Run, error)
+	GetRun(ctx context.Context, runID, ownerID string) (*response.Run, error)
+	CancelRun(ctx context.Context, threadID, runID, ownerID string) (*response.Run, error)
+	GetListMessage(ctx context.Context, option *request.ListMessage) (*response.List[response.Message], error)
+}
+
+// PreparedPrompt is a struct of prompt that is validated and has its conversation and attachments resolved
+// It is created by PreparePrompt, so the caller can report invalid prompt before streaming it
+type PreparedPrompt struct {
+	prompt       *request.Prompt
+	runRequest   *connector.RequestRun
+	conversation *repository.Conversation
+	attachments  []response.Attachment
 }
 
-func NewPromptUsecase(connector connector.Connector) PromptUsecase {
+func NewPromptUsecase(
+	connector connector.Connector,
+	conversationRepository repository.ConversationRepository,
+	toolRegistry ToolRegistry,
+	pollStrategy PollStrategy,
+	runPolicy RunPolicy,
+	fileUsecase FileUsecase,
+) PromptUsecase {
 	return &promptUsecase{
-		connector: connector,
+		connector:              connector,
+		conversationRepository: conversationRepository,
+		toolRegistry:           toolRegistry,
+		pollStrategy:           pollStrategy,
+		runPolicy:              runPolicy,
+		fileUsecase:            fileUsecase,
+		asyncRuns:              newAsyncRunTracker(),
 	}
 }
 
 type promptUsecase struct {
-	connector connector.Connector
+	connector              connector.Connector
+	conversationRepository repository.ConversationRepository
+	toolRegistry           ToolRegistry
+	pollStrategy           PollStrategy
+	runPolicy              RunPolicy
+	fileUsecase            FileUsecase
+	asyncRuns              *asyncRunTracker
 }
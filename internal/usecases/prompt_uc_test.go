@@ -1,16 +1,40 @@
 package usecases_test
 
 import (
+	"github.com/yonisaka/assistant/internal/entities/repository"
 	"github.com/yonisaka/assistant/internal/infrastructure/connector"
 	"github.com/yonisaka/assistant/internal/usecases"
 )
 
 type promptFields struct {
-	connector connector.Connector
+	connector              connector.Connector
+	conversationRepository repository.ConversationRepository
+	toolRegistry           usecases.ToolRegistry
+	pollStrategy           usecases.PollStrategy
+	runPolicy              usecases.RunPolicy
+	fileUsecase            usecases.FileUsecase
 }
 
 func promptSut(f promptFields) usecases.PromptUsecase {
+	// Poll without delay so test does not sleep
+	if f.pollStrategy == nil {
+		f.pollStrategy = usecases.NewBackoffPollStrategy(usecases.PollConfig{})
+	}
+
+	if f.runPolicy == nil {
+		f.runPolicy = usecases.NewRunPolicy(usecases.RunPolicyConfig{}, f.toolRegistry, nil)
+	}
+
+	if f.fileUsecase == nil {
+		f.fileUsecase = usecases.NewFileUsecase(f.connector, usecases.NewAssistantUsecase(f.connector))
+	}
+
 	return usecases.NewPromptUsecase(
 		f.connector,
+		f.conversationRepository,
+		f.toolRegistry,
+		f.pollStrategy,
+		f.runPolicy,
+		f.fileUsecase,
 	)
 }
@@ -0,0 +1,181 @@
+package usecases
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// RunRequest is a function to validate run options of the prompt against the allow-list and build the run request
+// When the prompt chooses a profile, the profile is the base of the run request and its rate limit is taken
+func (p *runPolicy) RunRequest(prompt *request.Prompt) (*connector.RequestRun, error) {
+	if prompt.Profile != "" {
+		return p.profileRunRequest(prompt)
+	}
+
+	runRequest := &connector.RequestRun{
+		AssistantID:            p.config.DefaultAssistantID,
+		Model:                  prompt.Model,
+		Instructions:           prompt.Instructions,
+		AdditionalInstructions: prompt.AdditionalInstructions,
+		Metadata:               prompt.Metadata,
+	}
+
+	if prompt.AssistantID != "" && prompt.AssistantID != p.config.DefaultAssistantID {
+		if !slices.Contains(p.config.AssistantIDs, prompt.AssistantID) {
+			return nil, fmt.Errorf("%w: assistant_id %s", ErrRunOptionNotAllowed, prompt.AssistantID)
+		}
+
+		runRequest.AssistantID = prompt.AssistantID
+	}
+
+	if err := p.validateOverrides(prompt, ""); err != nil {
+		return nil, err
+	}
+
+	tools, err := p.tools(prompt.Tools, p.config.Tools)
+	if err != nil {
+		return nil, err
+	}
+
+	runRequest.Tools = tools
+
+	return runRequest, nil
+}
+
+// AllowsFile is a function to check the file against the files of the profile or the allow-list of config
+func (p *runPolicy) AllowsFile(prompt *request.Prompt, fileID string) bool {
+	if prompt.Profile == "" {
+		return slices.Contains(p.config.FileIDs, fileID)
+	}
+
+	if p.profiles == nil {
+		return false
+	}
+
+	profile, err := p.profiles.Get(prompt.Profile)
+	if err != nil {
+		return false
+	}
+
+	return profile.AllowsFile(fileID)
+}
+
+// profileRunRequest is a function to build the run request from the profile chosen by the prompt
+// The prompt can only use the assistant and the tools of the profile
+func (p *runPolicy) profileRunRequest(prompt *request.Prompt) (*connector.RequestRun, error) {
+	if p.profiles == nil {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, prompt.Profile)
+	}
+
+	profile, err := p.profiles.Get(prompt.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if prompt.AssistantID != "" && prompt.AssistantID != profile.AssistantID {
+		return nil, fmt.Errorf("%w: assistant_id %s in profile %s", ErrRunOptionNotAllowed, prompt.AssistantID, profile.Name)
+	}
+
+	if err := p.validateOverrides(prompt, profile.Model); err != nil {
+		return nil, err
+	}
+
+	toolNames := profile.Tools
+	if len(prompt.Tools) > 0 {
+		toolNames = prompt.Tools
+	}
+
+	tools, err := p.tools(toolNames, profile.Tools)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rate limit is taken only by valid request
+	if err := p.profiles.Allow(profile.Name); err != nil {
+		return nil, err
+	}
+
+	runRequest := &connector.RequestRun{
+		AssistantID:            profile.AssistantID,
+		Model:                  profile.Model,
+		Instructions:           profile.Instructions,
+		AdditionalInstructions: prompt.AdditionalInstructions,
+		Tools:                  tools,
+		Metadata:               prompt.Metadata,
+	}
+
+	if prompt.Model != "" {
+		runRequest.Model = prompt.Model
+	}
+
+	if prompt.Instructions != "" {
+		runRequest.Instructions = prompt.Instructions
+	}
+
+	return runRequest, nil
+}
+
+// validateOverrides is a function to check model, instructions and metadata of the prompt
+// The default model is always allowed
+func (p *runPolicy) validateOverrides(prompt *request.Prompt, defaultModel string) error {
+	if prompt.Model != "" && prompt.Model != defaultModel && !slices.Contains(p.config.Models, prompt.Model) {
+		return fmt.Errorf("%w: model %s", ErrRunOptionNotAllowed, prompt.Model)
+	}
+
+	if (prompt.Instructions != "" || prompt.AdditionalInstructions != "") && !p.config.AllowInstructions {
+		return fmt.Errorf("%w: instructions", ErrRunOptionNotAllowed)
+	}
+
+	if err := validateMetadata(prompt.Metadata); err != nil {
+		return fmt.Errorf("%w: %w", ErrRunOptionInvalid, err)
+	}
+
+	return nil
+}
+
+// tools is a function to get tool definitions by their names, each name must be in the allowed names
+func (p *runPolicy) tools(names, allowed []string) ([]repository.AssistantTool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tools := make([]repository.AssistantTool, 0, len(names))
+
+	for _, name := range names {
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("%w: tool %s", ErrRunOptionNotAllowed, name)
+		}
+
+		tool, err := toolDefinition(name, p.toolRegistry)
+		if err != nil {
+			return nil, err
+		}
+
+		tools = append(tools, tool)
+	}
+
+	return tools, nil
+}
+
+// validateMetadata is a function to check metadata against the limits of OpenAI
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataKeys {
+		return fmt.Errorf("metadata has more than %d keys", maxMetadataKeys)
+	}
+
+	for key, value := range metadata {
+		if len(key) > maxMetadataKeyLength {
+			return fmt.Errorf("metadata key %s is longer than %d", key, maxMetadataKeyLength)
+		}
+
+		if len(value) > maxMetadataValueLength {
+			return fmt.Errorf("metadata value of %s is longer than %d", key, maxMetadataValueLength)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,260 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"strings"
+	"testing"
+)
+
+func TestRunPolicy_RunRequest(t *testing.T) {
+	type test struct {
+		prompt  *request.Prompt
+		want    *connector.RequestRun
+		wantErr error
+	}
+
+	toolRegistry := usecases.NewToolRegistry()
+	require.NoError(t, toolRegistry.Register(usecases.Tool{
+		Name:       "get_time",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+			return "10:00", nil
+		},
+	}))
+
+	config := usecases.RunPolicyConfig{
+		DefaultAssistantID: "asst-default",
+		AssistantIDs:       []string{"asst-support"},
+		Models:             []string{"gpt-4-turbo-preview"},
+		Tools:              []string{"retrieval", "get_time", "get_weather"},
+		AllowInstructions:  true,
+	}
+
+	tests := map[string]test{
+		"Given prompt without run options, When run request is built, Return default assistant": {
+			prompt: &request.Prompt{Message: "Hello"},
+			want:   &connector.RequestRun{AssistantID: "asst-default"},
+		},
+		"Given prompt with allowed run options, When run request is built, Return overridden run request": {
+			prompt: &request.Prompt{
+				Message:                "Hello",
+				AssistantID:            "asst-support",
+				Model:                  "gpt-4-turbo-preview",
+				Instructions:           "Be brief",
+				AdditionalInstructions: "Answer in Bahasa",
+				Tools:                  []string{"retrieval", "get_time"},
+				Metadata:               map[string]string{"persona": "support"},
+			},
+			want: &connector.RequestRun{
+				AssistantID:            "asst-support",
+				Model:                  "gpt-4-turbo-preview",
+				Instructions:           "Be brief",
+				AdditionalInstructions: "Answer in Bahasa",
+				Tools: []repository.AssistantTool{
+					{Type: connector.OpenAIToolTypeRetrieval},
+					{
+						Type: connector.OpenAIToolTypeFunction,
+						Function: &repository.AssistantFunction{
+							Name:       "get_time",
+							Parameters: json.RawMessage(`{"type":"object"}`),
+						},
+					},
+				},
+				Metadata: map[string]string{"persona": "support"},
+			},
+		},
+		"Given prompt with assistant out of allow-list, When run request is built, Return not allowed error": {
+			prompt:  &request.Prompt{Message: "Hello", AssistantID: "asst-other"},
+			wantErr: usecases.ErrRunOptionNotAllowed,
+		},
+		"Given prompt with model out of allow-list, When run request is built, Return not allowed error": {
+			prompt:  &request.Prompt{Message: "Hello", Model: "gpt-3.5-turbo"},
+			wantErr: usecases.ErrRunOptionNotAllowed,
+		},
+		"Given prompt with tool out of allow-list, When run request is built, Return not allowed error": {
+			prompt:  &request.Prompt{Message: "Hello", Tools: []string{"code_interpreter"}},
+			wantErr: usecases.ErrRunOptionNotAllowed,
+		},
+		"Given prompt with allowed tool that is not registered, When run request is built, Return invalid error": {
+			prompt:  &request.Prompt{Message: "Hello", Tools: []string{"get_weather"}},
+			wantErr: usecases.ErrRunOptionInvalid,
+		},
+		"Given prompt with too long metadata value, When run request is built, Return invalid error": {
+			prompt:  &request.Prompt{Message: "Hello", Metadata: map[string]string{"note": strings.Repeat("a", 513)}},
+			wantErr: usecases.ErrRunOptionInvalid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := usecases.NewRunPolicy(config, toolRegistry, nil)
+
+			got, err := sut.RunRequest(tt.prompt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("Given prompt with instructions, When instructions are not allowed, Return not allowed error", func(t *testing.T) {
+		sut := usecases.NewRunPolicy(usecases.RunPolicyConfig{}, nil, nil)
+
+		got, err := sut.RunRequest(&request.Prompt{Message: "Hello", AdditionalInstructions: "Be brief"})
+
+		assert.ErrorIs(t, err, usecases.ErrRunOptionNotAllowed)
+		assert.Nil(t, got)
+	})
+}
+
+func TestRunPolicy_RunRequestProfile(t *testing.T) {
+	type test struct {
+		prompt  *request.Prompt
+		want    *connector.RequestRun
+		wantErr error
+	}
+
+	profiles, err := usecases.NewProfileRegistry([]usecases.Profile{
+		{
+			Name:         "support",
+			AssistantID:  "asst-support",
+			Instructions: "You are a support agent",
+			Model:        "gpt-4-turbo-preview",
+			Tools:        []string{"retrieval", "code_interpreter"},
+		},
+		{
+			Name:        "limited",
+			AssistantID: "asst-limited",
+			RateLimit:   usecases.ProfileRateLimit{RequestsPerMinute: 1, Burst: 1},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	// The only request allowed by the limited profile is taken here
+	require.NoError(t, profiles.Allow("limited"))
+
+	config := usecases.RunPolicyConfig{
+		DefaultAssistantID: "asst-default",
+		Models:             []string{"gpt-3.5-turbo"},
+	}
+
+	tests := map[string]test{
+		"Given prompt with profile, When run request is built, Return run request of the profile": {
+			prompt: &request.Prompt{Message: "Hello", Profile: "support"},
+			want: &connector.RequestRun{
+				AssistantID:  "asst-support",
+				Model:        "gpt-4-turbo-preview",
+				Instructions: "You are a support agent",
+				Tools: []repository.AssistantTool{
+					{Type: connector.OpenAIToolTypeRetrieval},
+					{Type: connector.OpenAIToolTypeCodeInterpreter},
+				},
+			},
+		},
+		"Given prompt with profile and subset of its tools, When run request is built, Return run request with the subset": {
+			prompt: &request.Prompt{Message: "Hello", Profile: "support", Model: "gpt-3.5-turbo", Tools: []string{"retrieval"}},
+			want: &connector.RequestRun{
+				AssistantID:  "asst-support",
+				Model:        "gpt-3.5-turbo",
+				Instructions: "You are a support agent",
+				Tools:        []repository.AssistantTool{{Type: connector.OpenAIToolTypeRetrieval}},
+			},
+		},
+		"Given prompt with profile and other assistant, When run request is built, Return not allowed error": {
+			prompt:  &request.Prompt{Message: "Hello", Profile: "support", AssistantID: "asst-default"},
+			wantErr: usecases.ErrRunOptionNotAllowed,
+		},
+		"Given prompt with profile and tool out of the profile, When run request is built, Return not allowed error": {
+			prompt:  &request.Prompt{Message: "Hello", Profile: "support", Tools: []string{"get_current_time"}},
+			wantErr: usecases.ErrRunOptionNotAllowed,
+		},
+		"Given prompt with unknown profile, When run request is built, Return not found error": {
+			prompt:  &request.Prompt{Message: "Hello", Profile: "marketing"},
+			wantErr: usecases.ErrProfileNotFound,
+		},
+		"Given prompt with profile over its rate limit, When run request is built, Return rate limited error": {
+			prompt:  &request.Prompt{Message: "Hello", Profile: "limited"},
+			wantErr: usecases.ErrProfileRateLimited,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := usecases.NewRunPolicy(config, nil, profiles)
+
+			got, err := sut.RunRequest(tt.prompt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRunPolicy_AllowsFile(t *testing.T) {
+	type test struct {
+		prompt *request.Prompt
+		fileID string
+		want   bool
+	}
+
+	profiles, err := usecases.NewProfileRegistry([]usecases.Profile{
+		{
+			Name:        "support",
+			AssistantID: "asst-support",
+			FileIDs:     []string{"file-support"},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	config := usecases.RunPolicyConfig{
+		DefaultAssistantID: "asst-default",
+		FileIDs:            []string{"file-shared"},
+	}
+
+	tests := map[string]test{
+		"Given prompt without profile and file in allow-list, When file is checked, Return true": {
+			prompt: &request.Prompt{Message: "Hello"},
+			fileID: "file-shared",
+			want:   true,
+		},
+		"Given prompt without profile and file out of allow-list, When file is checked, Return false": {
+			prompt: &request.Prompt{Message: "Hello"},
+			fileID: "file-support",
+		},
+		"Given prompt with profile and file of the profile, When file is checked, Return true": {
+			prompt: &request.Prompt{Message: "Hello", Profile: "support"},
+			fileID: "file-support",
+			want:   true,
+		},
+		"Given prompt with profile and file of allow-list only, When file is checked, Return false": {
+			prompt: &request.Prompt{Message: "Hello", Profile: "support"},
+			fileID: "file-shared",
+		},
+		"Given prompt with unknown profile, When file is checked, Return false": {
+			prompt: &request.Prompt{Message: "Hello", Profile: "marketing"},
+			fileID: "file-support",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			sut := usecases.NewRunPolicy(config, nil, profiles)
+
+			assert.Equal(t, tt.want, sut.AllowsFile(tt.prompt, tt.fileID))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"errors"
+
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// RunPolicy is an interface to decide which run options a prompt is allowed to override
+// It is injected so the allow-list can be set by config
+type RunPolicy interface {
+	// RunRequest returns the run request of the prompt, the default assistant is used when the prompt does not choose one
+	// It returns ErrRunOptionNotAllowed or ErrRunOptionInvalid when the prompt overrides run option that is not allowed,
+	// and ErrProfileNotFound or ErrProfileRateLimited when the profile of the prompt cannot be used
+	RunRequest(prompt *request.Prompt) (*connector.RequestRun, error)
+	// AllowsFile returns true when the prompt can attach the file, the profile of the prompt decides it when it is chosen
+	AllowsFile(prompt *request.Prompt, fileID string) bool
+}
+
+// RunPolicyConfig is a struct to set allow-list of run options
+// Empty allow-list means the option cannot be overridden by the prompt
+type RunPolicyConfig struct {
+	DefaultAssistantID string
+	AssistantIDs       []string
+	Models             []string
+	// Tools are names of built-in tool type, e.g. retrieval, or function in tool registry
+	Tools []string
+	// AllowInstructions allows the prompt to replace or extend the instructions of the assistant
+	AllowInstructions bool
+	// FileIDs are files that every prompt without profile can attach to its message
+	FileIDs []string
+}
+
+func NewRunPolicy(config RunPolicyConfig, toolRegistry ToolRegistry, profiles ProfileRegistry) RunPolicy {
+	return &runPolicy{
+		config:       config,
+		toolRegistry: toolRegistry,
+		profiles:     profiles,
+	}
+}
+
+type runPolicy struct {
+	config       RunPolicyConfig
+	toolRegistry ToolRegistry
+	profiles     ProfileRegistry
+}
+
+const (
+	// Metadata limits of OpenAI object
+	maxMetadataKeys        = 16
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 512
+)
+
+var (
+	ErrRunOptionNotAllowed = errors.New("run option is not allowed")
+	ErrRunOptionInvalid    = errors.New("run option is invalid")
+)
@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// GetListThread is a function to get every thread of the owner from conversation store
+// Pinned threads come first, then the latest updated thread
+func (u *threadUsecase) GetListThread(ctx context.Context, option *request.ListThread) (*response.List[response.Thread], error) {
+	conversations, err := u.conversationRepository.List(ctx, option.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]response.Thread, 0, len(conversations))
+	for i := range conversations {
+		if option.Tag != "" && !slices.Contains(conversations[i].Tags, option.Tag) {
+			continue
+		}
+
+		threads = append(threads, toThread(&conversations[i]))
+	}
+
+	// Store sorts by the latest update, so stable sort keeps that order within pinned and unpinned
+	sort.SliceStable(threads, func(i, j int) bool {
+		return threads[i].Pinned && !threads[j].Pinned
+	})
+
+	list := &response.List[response.Thread]{Data: threads}
+	if len(threads) > 0 {
+		list.LastID = threads[len(threads)-1].ID
+	}
+
+	return list, nil
+}
+
+// GetThread is a function to get thread of the owner from conversation store
+func (u *threadUsecase) GetThread(ctx context.Context, threadID, ownerID string) (*response.Thread, error) {
+	conversation, err := getOwnedConversation(ctx, u.conversationRepository, threadID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := toThread(conversation)
+
+	return &thread, nil
+}
+
+// UpdateThread is a function to update metadata of the owner's thread
+// It will modify metadata of the thread in OpenAI first, then store it in the conversation,
+// so the conversation is not changed when OpenAI rejects it
+// Only the fields sent are stored, so concurrent change of the conversation, e.g. by a prompt, is kept
+func (u *threadUsecase) UpdateThread(ctx context.Context, threadID, ownerID string, thread *request.Thread) (*response.Thread, error) {
+	conversation, err := getOwnedConversation(ctx, u.conversationRepository, threadID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := threadChange(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	change(conversation)
+
+	if err := u.modifyThread(ctx, conversation); err != nil {
+		return nil, err
+	}
+
+	conversation, err = u.conversationRepository.Modify(ctx, conversation.ID, change)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toThread(conversation)
+
+	return &result, nil
+}
+
+// threadChange is a function to validate thread metadata from client and get the change of conversation
+func threadChange(thread *request.Thread) (func(conversation *repository.Conversation), error) {
+	var title string
+
+	if thread.Title != nil {
+		title = strings.TrimSpace(*thread.Title)
+		if title == "" || len([]rune(title)) > maxThreadTitleLength {
+			return nil, fmt.Errorf("%w: title must be between 1 and %d characters", ErrThreadInvalid, maxThreadTitleLength)
+		}
+	}
+
+	var tags []string
+
+	if thread.Tags != nil {
+		var err error
+
+		tags, err = threadTags(*thread.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return func(conversation *repository.Conversation) {
+		if thread.Title != nil {
+			conversation.Title = title
+		}
+
+		if thread.Tags != nil {
+			conversation.Tags = tags
+		}
+
+		if thread.Pinned != nil {
+			conversation.Pinned = *thread.Pinned
+		}
+	}, nil
+}
+
+// DeleteThread is a function to delete the owner's thread in OpenAI and its conversation
+// Thread that is already deleted in OpenAI only deletes the conversation
+func (u *threadUsecase) DeleteThread(ctx context.Context, threadID, ownerID string) error {
+	conversation, err := getOwnedConversation(ctx, u.conversationRepository, threadID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    fmt.Sprintf("/threads/%s", threadID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	var result *connector.OpenAIDeleted
+	err = u.connector.Send(ctx, httpRequestOption, &result)
+
+	switch {
+	case connector.IsNotFound(err):
+		log.Warnw("Thread Already Deleted:", "id", threadID)
+	case err != nil:
+		return err
+	case result == nil || !result.Deleted:
+		return connector.ErrDelete
+	}
+
+	if err := u.conversationRepository.Delete(ctx, conversation.ID); err != nil {
+		return err
+	}
+
+	log.Infow("Thread Deleted:", "id", threadID, "conversation_id", conversation.ID)
+
+	return nil
+}
+
+// GetFileContent is a function to get content of file of the owner's thread from OpenAI API
+// Files are shared by every owner in OpenAI, so only the files recorded in the conversation can be downloaded,
+// i.e. the files uploaded with its prompts and the files linked by its assistant messages
+func (u *threadUsecase) GetFileContent(ctx context.Context, threadID, fileID, ownerID string) (*response.FileContent, error) {
+	conversation, err := getOwnedConversation(ctx, u.conversationRepository, threadID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(conversation.FileIDs, fileID) {
+		return nil, fmt.Errorf("%w: file_id=%s", ErrThreadFileNotFound, fileID)
+	}
+
+	return u.fileUsecase.GetFileContent(ctx, fileID)
+}
+
+// modifyThread is a function to set title, tags and pinned of the conversation as metadata of the thread in OpenAI
+func (u *threadUsecase) modifyThread(ctx context.Context, conversation *repository.Conversation) error {
+	requestBodyThread := connector.RequestThread{
+		Metadata: map[string]string{
+			threadMetadataTitle:  conversation.Title,
+			threadMetadataTags:   strings.Join(conversation.Tags, ","),
+			threadMetadataPinned: strconv.FormatBool(conversation.Pinned),
+		},
+	}
+
+	var bufThread bytes.Buffer
+	if err := json.NewEncoder(&bufThread).Encode(requestBodyThread); err != nil {
+		return err
+	}
+
+	httpRequestOption := &connector.RequestOption{
+		Method: http.MethodPost,
+		URL:    fmt.Sprintf("/threads/%s", conversation.ThreadID),
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+		Body: &bufThread,
+	}
+
+	var result *repository.Thread
+	if err := u.connector.Send(ctx, httpRequestOption, &result); err != nil {
+		return err
+	}
+
+	if result == nil || result.ID == "" {
+		return connector.ErrModifyThread
+	}
+
+	log.Infow("Thread Modified:", "id", result.ID)
+
+	return nil
+}
+
+// threadTags is a function to validate tags from client, tags are trimmed and duplicate tags are removed
+func threadTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "" || len(tag) > maxThreadTagLength || strings.Contains(tag, ",") {
+			return nil, fmt.Errorf("%w: tag must be between 1 and %d characters without comma", ErrThreadInvalid, maxThreadTagLength)
+		}
+
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	if len(result) > maxThreadTags {
+		return nil, fmt.Errorf("%w: thread has more than %d tags", ErrThreadInvalid, maxThreadTags)
+	}
+
+	return result, nil
+}
+
+// toThread is a function to convert conversation into thread sent to client
+func toThread(conversation *repository.Conversation) response.Thread {
+	tags := conversation.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return response.Thread{
+		ID:             conversation.ThreadID,
+		ConversationID: conversation.ID,
+		AssistantID:    conversation.AssistantID,
+		Title:          conversation.Title,
+		Tags:           tags,
+		Pinned:         conversation.Pinned,
+		CreatedAt:      conversation.CreatedAt,
+		UpdatedAt:      conversation.UpdatedAt,
+	}
+}
@@ -0,0 +1,558 @@
+package usecases_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestThreadUsecase_GetListThread(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		option *request.ListThread
+	}
+
+	type test struct {
+		fields  threadFields
+		args    args
+		want    *response.List[response.Thread]
+		wantErr error
+	}
+
+	conversations := []repository.Conversation{
+		{ID: "conversation-3", OwnerID: "user-1", ThreadID: "thread-3", Title: "Latest", UpdatedAt: 30},
+		{ID: "conversation-2", OwnerID: "user-1", ThreadID: "thread-2", Title: "Pinned", Tags: []string{"work"}, Pinned: true, UpdatedAt: 20},
+		{ID: "conversation-1", OwnerID: "user-1", ThreadID: "thread-1", Title: "Oldest", Tags: []string{"work"}, UpdatedAt: 10},
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get List Thread, When repository executed successfully, Return pinned threads first": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:    context.Background(),
+				option: &request.ListThread{OwnerID: "user-1"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().List(args.ctx, "user-1").Return(conversations, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.List[response.Thread]{
+					Data: []response.Thread{
+						{ID: "thread-2", ConversationID: "conversation-2", Title: "Pinned", Tags: []string{"work"}, Pinned: true, UpdatedAt: 20},
+						{ID: "thread-3", ConversationID: "conversation-3", Title: "Latest", Tags: []string{}, UpdatedAt: 30},
+						{ID: "thread-1", ConversationID: "conversation-1", Title: "Oldest", Tags: []string{"work"}, UpdatedAt: 10},
+					},
+					LastID: "thread-1",
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Get List Thread with tag, When repository executed successfully, Return threads that have the tag": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:    context.Background(),
+				option: &request.ListThread{OwnerID: "user-1", Tag: "work"},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().List(args.ctx, "user-1").Return(conversations, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.List[response.Thread]{
+					Data: []response.Thread{
+						{ID: "thread-2", ConversationID: "conversation-2", Title: "Pinned", Tags: []string{"work"}, Pinned: true, UpdatedAt: 20},
+						{ID: "thread-1", ConversationID: "conversation-1", Title: "Oldest", Tags: []string{"work"}, UpdatedAt: 10},
+					},
+					LastID: "thread-1",
+				},
+				wantErr: nil,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := threadSut(tt.fields)
+
+			got, err := sut.GetListThread(tt.args.ctx, tt.args.option)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestThreadUsecase_UpdateThread(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		threadID string
+		ownerID  string
+		thread   *request.Thread
+	}
+
+	type test struct {
+		fields  threadFields
+		args    args
+		want    *response.Thread
+		wantErr error
+	}
+
+	title := "  Sales report  "
+	tags := []string{"work", " q1 ", "work"}
+	pinned := true
+
+	conversation := func() *repository.Conversation {
+		return &repository.Conversation{
+			ID:        "conversation-1",
+			OwnerID:   "user-1",
+			ThreadID:  "thread-1",
+			Title:     "Hello",
+			CreatedAt: 10,
+			UpdatedAt: 20,
+		}
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Update Thread, When repository executed successfully, Return updated thread": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+				thread:   &request.Thread{Title: &title, Tags: &tags, Pinned: &pinned},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation(), nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, option *connector.RequestOption, _ interface{}) error {
+					assert.Equal(t, http.MethodPost, option.Method)
+					assert.Equal(t, "/threads/thread-1", option.URL)
+
+					body, err := io.ReadAll(option.Body)
+					require.NoError(t, err)
+					assert.JSONEq(t, `{"metadata":{"title":"Sales report","tags":"work,q1","pinned":"true"}}`, string(body))
+
+					return nil
+				},
+			).SetArg(2, &repository.Thread{ID: "thread-1"})
+
+			mockConversationRepository.EXPECT().Modify(args.ctx, "conversation-1", gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, change func(*repository.Conversation)) (*repository.Conversation, error) {
+					// File added by a prompt in flight is kept
+					stored := conversation()
+					stored.FileIDs = []string{"file-1"}
+
+					change(stored)
+					stored.UpdatedAt = 30
+
+					assert.Equal(t, []string{"file-1"}, stored.FileIDs)
+
+					return stored, nil
+				},
+			)
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.Thread{
+					ID:             "thread-1",
+					ConversationID: "conversation-1",
+					Title:          "Sales report",
+					Tags:           []string{"work", "q1"},
+					Pinned:         true,
+					CreatedAt:      10,
+					UpdatedAt:      30,
+				},
+				wantErr: nil,
+			}
+		},
+		"Given request of Update Thread with blank title, When it is validated, Return thread invalid error": func(t *testing.T, ctrl *gomock.Controller) test {
+			blank := " "
+
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+				thread:   &request.Thread{Title: &blank},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation(), nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: usecases.ErrThreadInvalid,
+			}
+		},
+		"Given request of Update Thread, When thread belongs to other owner, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-2",
+				thread:   &request.Thread{Pinned: &pinned},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation(), nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+		"Given valid request of Update Thread, When OpenAI fails to modify thread, Return error without updating conversation": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+				thread:   &request.Thread{Pinned: &pinned},
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation(), nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				want:    nil,
+				wantErr: connector.ErrModifyThread,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := threadSut(tt.fields)
+
+			got, err := sut.UpdateThread(tt.args.ctx, tt.args.threadID, tt.args.ownerID, tt.args.thread)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestThreadUsecase_DeleteThread(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		threadID string
+		ownerID  string
+	}
+
+	type test struct {
+		fields  threadFields
+		args    args
+		wantErr error
+	}
+
+	deleteRequestOption := &connector.RequestOption{
+		Method: http.MethodDelete,
+		URL:    "/threads/thread-1",
+		CustomHeader: map[string]string{
+			connector.HeaderKeyOpenAIBeta: connector.AssistantV1,
+		},
+	}
+
+	conversation := &repository.Conversation{
+		ID:       "conversation-1",
+		OwnerID:  "user-1",
+		ThreadID: "thread-1",
+	}
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Delete Thread, When thread is deleted in OpenAI, Return no error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Delete(args.ctx, "conversation-1").Return(nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, deleteRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID:      "thread-1",
+				Deleted: true,
+			})
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Delete Thread, When thread is already deleted in OpenAI, Return no error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+			mockConversationRepository.EXPECT().Delete(args.ctx, "conversation-1").Return(nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, deleteRequestOption, gomock.Any()).Return(&connector.APIError{
+				HTTPStatus: http.StatusNotFound,
+				Message:    "No thread found with id 'thread-1'.",
+			})
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: nil,
+			}
+		},
+		"Given valid request of Delete Thread, When OpenAI fails, Return error without deleting conversation": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().Send(args.ctx, deleteRequestOption, gomock.Any()).Return(nil).SetArg(2, &connector.OpenAIDeleted{
+				ID:      "thread-1",
+				Deleted: false,
+			})
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: connector.ErrDelete,
+			}
+		},
+		"Given request of Delete Thread, When thread belongs to other owner, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				ownerID:  "user-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := threadSut(tt.fields)
+
+			err := sut.DeleteThread(tt.args.ctx, tt.args.threadID, tt.args.ownerID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestThreadUsecase_GetFileContent(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		threadID string
+		fileID   string
+		ownerID  string
+	}
+
+	type test struct {
+		fields  threadFields
+		args    args
+		want    *response.FileContent
+		wantErr error
+	}
+
+	conversation := &repository.Conversation{
+		ID:       "conversation-1",
+		OwnerID:  "user-1",
+		ThreadID: "thread-1",
+		FileIDs:  []string{"file-1"},
+	}
+
+	body := io.NopCloser(strings.NewReader("hello world"))
+
+	tests := map[string]func(t *testing.T, ctrl *gomock.Controller) test{
+		"Given valid request of Get File Content, When file is recorded in the conversation, Return the content": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				fileID:   "file-1",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+
+			mockConnector := connector.NewGoMockConnector(ctrl)
+			mockConnector.EXPECT().SendRaw(args.ctx, &connector.RequestOption{
+				Method: http.MethodGet,
+				URL:    "/files/file-1/content",
+			}).Return(&connector.RawResponse{
+				Body:          body,
+				Header:        http.Header{"Content-Type": []string{"image/png"}},
+				ContentLength: 11,
+			}, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              mockConnector,
+					conversationRepository: mockConversationRepository,
+				},
+				args: args,
+				want: &response.FileContent{
+					Body:          body,
+					ContentType:   "image/png",
+					ContentLength: 11,
+				},
+			}
+		},
+		"Given request of Get File Content, When file is not recorded in the conversation, Return file not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				fileID:   "file-2",
+				ownerID:  "user-1",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: usecases.ErrThreadFileNotFound,
+			}
+		},
+		"Given request of Get File Content, When thread belongs to other owner, Return not found error": func(t *testing.T, ctrl *gomock.Controller) test {
+			args := args{
+				ctx:      context.Background(),
+				threadID: "thread-1",
+				fileID:   "file-1",
+				ownerID:  "user-2",
+			}
+
+			mockConversationRepository := repository.NewGoMockConversationRepository(ctrl)
+			mockConversationRepository.EXPECT().GetByThreadID(args.ctx, "thread-1").Return(conversation, nil)
+
+			return test{
+				fields: threadFields{
+					connector:              connector.NewGoMockConnector(ctrl),
+					conversationRepository: mockConversationRepository,
+				},
+				args:    args,
+				wantErr: repository.ErrConversationNotFound,
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tt := testFn(t, ctrl)
+
+			sut := threadSut(tt.fields)
+
+			got, err := sut.GetFileContent(tt.args.ctx, tt.args.threadID, tt.args.fileID, tt.args.ownerID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
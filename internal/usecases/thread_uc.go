@@ -0,0 +1,54 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/entities/request"
+	"github.com/yonisaka/assistant/internal/entities/response"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+type ThreadUsecase interface {
+	GetListThread(ctx context.Context, option *request.ListThread) (*response.List[response.Thread], error)
+	GetThread(ctx context.Context, threadID, ownerID string) (*response.Thread, error)
+	UpdateThread(ctx context.Context, threadID, ownerID string, thread *request.Thread) (*response.Thread, error)
+	DeleteThread(ctx context.Context, threadID, ownerID string) error
+	GetFileContent(ctx context.Context, threadID, fileID, ownerID string) (*response.FileContent, error)
+}
+
+func NewThreadUsecase(
+	connector connector.Connector,
+	conversationRepository repository.ConversationRepository,
+	fileUsecase FileUsecase,
+) ThreadUsecase {
+	return &threadUsecase{
+		connector:              connector,
+		conversationRepository: conversationRepository,
+		fileUsecase:            fileUsecase,
+	}
+}
+
+type threadUsecase struct {
+	connector              connector.Connector
+	conversationRepository repository.ConversationRepository
+	fileUsecase            FileUsecase
+}
+
+const (
+	// Thread limits, tags are joined by comma in thread metadata so they must fit its value limit
+	maxThreadTitleLength = 256
+	maxThreadTags        = 10
+	maxThreadTagLength   = 32
+
+	// Thread metadata keys in OpenAI
+	threadMetadataTitle  = "title"
+	threadMetadataTags   = "tags"
+	threadMetadataPinned = "pinned"
+)
+
+var (
+	ErrThreadInvalid      = errors.New("thread is invalid")
+	ErrThreadFileNotFound = errors.New("file not found in thread")
+)
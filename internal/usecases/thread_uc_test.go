@@ -0,0 +1,20 @@
+package usecases_test
+
+import (
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+)
+
+type threadFields struct {
+	connector              connector.Connector
+	conversationRepository repository.ConversationRepository
+}
+
+func threadSut(f threadFields) usecases.ThreadUsecase {
+	return usecases.NewThreadUsecase(
+		f.connector,
+		f.conversationRepository,
+		usecases.NewFileUsecase(f.connector, usecases.NewAssistantUsecase(f.connector)),
+	)
+}
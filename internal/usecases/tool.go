@@ -0,0 +1,161 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// Register is a function to register tool by its name
+func (r *toolRegistry) Register(tool Tool) error {
+	if tool.Name == "" || tool.Handler == nil {
+		return ErrToolInvalid
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tools[tool.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrToolExists, tool.Name)
+	}
+
+	r.tools[tool.Name] = tool
+
+	return nil
+}
+
+// Definitions is a function to get function definitions of registered tools, sorted by name
+// The definitions can be set as tools of assistant or run
+func (r *toolRegistry) Definitions() []repository.AssistantTool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]repository.AssistantTool, 0, len(r.tools))
+
+	for _, tool := range r.tools {
+		definitions = append(definitions, repository.AssistantTool{
+			Type: connector.OpenAIToolTypeFunction,
+			Function: &repository.AssistantFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Function.Name < definitions[j].Function.Name
+	})
+
+	return definitions
+}
+
+// Execute is a function to run handlers of the tool calls in parallel
+// Every tool call gets an output in the same order, failure is reported to the model as error output
+func (r *toolRegistry) Execute(ctx context.Context, toolCalls []connector.ToolCall) []connector.ToolOutput {
+	outputs := make([]connector.ToolOutput, len(toolCalls))
+
+	var wg sync.WaitGroup
+
+	for i, toolCall := range toolCalls {
+		wg.Add(1)
+
+		go func(i int, toolCall connector.ToolCall) {
+			defer wg.Done()
+
+			output, err := r.execute(ctx, toolCall)
+			if err != nil {
+				log.Warnw("Tool Failed:", "id", toolCall.ID, "name", toolCall.Function.Name, "error", err)
+				output = toolErrorOutput(err)
+			}
+
+			outputs[i] = connector.ToolOutput{
+				ToolCallID: toolCall.ID,
+				Output:     output,
+			}
+		}(i, toolCall)
+	}
+
+	wg.Wait()
+
+	return outputs
+}
+
+// execute is a function to run handler of a tool call within its timeout
+// The handler result is dropped when it does not return in time
+func (r *toolRegistry) execute(ctx context.Context, toolCall connector.ToolCall) (string, error) {
+	r.mu.RLock()
+	tool, ok := r.tools[toolCall.Function.Name]
+	r.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrToolUnknown, toolCall.Function.Name)
+	}
+
+	timeout := tool.Timeout
+	if timeout <= 0 {
+		timeout = DefaultToolTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		output string
+		err    error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- result{err: fmt.Errorf("tool panicked: %v", recovered)}
+			}
+		}()
+
+		output, err := tool.Handler(ctx, json.RawMessage(toolCall.Function.Arguments))
+		done <- result{output: output, err: err}
+	}()
+
+	log.Infow("Tool Called:", "id", toolCall.ID, "name", toolCall.Function.Name)
+
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w after %s", ErrToolTimeout, timeout)
+		}
+
+		return "", ctx.Err()
+	case res := <-done:
+		return res.output, res.err
+	}
+}
+
+// toolErrorOutput is a function to build tool output that tells the model the call failed
+func toolErrorOutput(err error) string {
+	output, _ := json.Marshal(map[string]string{"error": err.Error()})
+
+	return string(output)
+}
+
+// CurrentTimeTool is a function to get built-in tool that returns the current time of the service
+func CurrentTimeTool() Tool {
+	return Tool{
+		Name:        "get_current_time",
+		Description: "Get the current date and time in RFC 3339 format",
+		Parameters:  json.RawMessage(`{"type":"object","properties":{}}`),
+		Timeout:     time.Second,
+		Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+			return time.Now().Format(time.RFC3339), nil
+		},
+	}
+}
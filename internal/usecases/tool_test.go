@@ -0,0 +1,122 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+	"github.com/yonisaka/assistant/internal/usecases"
+	"testing"
+	"time"
+)
+
+func TestToolRegistry_Register(t *testing.T) {
+	sut := usecases.NewToolRegistry()
+
+	require.NoError(t, sut.Register(usecases.CurrentTimeTool()))
+	assert.ErrorIs(t, sut.Register(usecases.CurrentTimeTool()), usecases.ErrToolExists)
+	assert.ErrorIs(t, sut.Register(usecases.Tool{Name: "no_handler"}), usecases.ErrToolInvalid)
+
+	definitions := sut.Definitions()
+	require.Len(t, definitions, 1)
+	assert.Equal(t, connector.OpenAIToolTypeFunction, definitions[0].Type)
+	assert.Equal(t, "get_current_time", definitions[0].Function.Name)
+}
+
+func TestToolRegistry_Execute(t *testing.T) {
+	type test struct {
+		tools     []usecases.Tool
+		toolCalls []connector.ToolCall
+		want      []connector.ToolOutput
+	}
+
+	echo := usecases.Tool{
+		Name: "echo",
+		Handler: func(_ context.Context, arguments json.RawMessage) (string, error) {
+			var args struct {
+				Text string `json:"text"`
+			}
+
+			if err := json.Unmarshal(arguments, &args); err != nil {
+				return "", err
+			}
+
+			return args.Text, nil
+		},
+	}
+
+	tests := map[string]func(t *testing.T) test{
+		"Given tool calls, When handlers succeed, Return outputs in the same order": func(t *testing.T) test {
+			return test{
+				tools: []usecases.Tool{echo},
+				toolCalls: []connector.ToolCall{
+					{ID: "call-1", Type: "function", Function: connector.ToolCallFunction{Name: "echo", Arguments: `{"text":"one"}`}},
+					{ID: "call-2", Type: "function", Function: connector.ToolCallFunction{Name: "echo", Arguments: `{"text":"two"}`}},
+				},
+				want: []connector.ToolOutput{
+					{ToolCallID: "call-1", Output: "one"},
+					{ToolCallID: "call-2", Output: "two"},
+				},
+			}
+		},
+		"Given tool calls, When tool is unknown or fails, Return error outputs to the model": func(t *testing.T) test {
+			failing := usecases.Tool{
+				Name: "failing",
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					return "", errors.New("boom")
+				},
+			}
+
+			return test{
+				tools: []usecases.Tool{failing},
+				toolCalls: []connector.ToolCall{
+					{ID: "call-1", Function: connector.ToolCallFunction{Name: "failing"}},
+					{ID: "call-2", Function: connector.ToolCallFunction{Name: "missing"}},
+				},
+				want: []connector.ToolOutput{
+					{ToolCallID: "call-1", Output: `{"error":"boom"}`},
+					{ToolCallID: "call-2", Output: `{"error":"unknown tool: missing"}`},
+				},
+			}
+		},
+		"Given tool calls, When handler exceeds its timeout, Return timeout output": func(t *testing.T) test {
+			slow := usecases.Tool{
+				Name:    "slow",
+				Timeout: 10 * time.Millisecond,
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					time.Sleep(time.Second)
+					return "late", nil
+				},
+			}
+
+			return test{
+				tools: []usecases.Tool{slow, echo},
+				toolCalls: []connector.ToolCall{
+					{ID: "call-1", Function: connector.ToolCallFunction{Name: "slow"}},
+					{ID: "call-2", Function: connector.ToolCallFunction{Name: "echo", Arguments: `{"text":"fast"}`}},
+				},
+				want: []connector.ToolOutput{
+					{ToolCallID: "call-1", Output: `{"error":"tool timed out after 10ms"}`},
+					{ToolCallID: "call-2", Output: "fast"},
+				},
+			}
+		},
+	}
+
+	for name, testFn := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt := testFn(t)
+
+			sut := usecases.NewToolRegistry()
+			for _, tool := range tt.tools {
+				require.NoError(t, sut.Register(tool))
+			}
+
+			got := sut.Execute(context.Background(), tt.toolCalls)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
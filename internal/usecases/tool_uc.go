@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yonisaka/assistant/internal/entities/repository"
+	"github.com/yonisaka/assistant/internal/infrastructure/connector"
+)
+
+// ToolHandler is a function to handle function call from the assistant
+// Arguments is the JSON arguments generated by the model, the returned string is sent back as tool output
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// Tool is a struct of function that can be called by the assistant
+type Tool struct {
+	Name        string
+	Description string
+	// Parameters is the JSON schema of the function arguments
+	Parameters json.RawMessage
+	// Timeout is the time limit of the handler, DefaultToolTimeout is used when it is zero
+	Timeout time.Duration
+	Handler ToolHandler
+}
+
+type ToolRegistry interface {
+	Register(tool Tool) error
+	Definitions() []repository.AssistantTool
+	Execute(ctx context.Context, toolCalls []connector.ToolCall) []connector.ToolOutput
+}
+
+func NewToolRegistry() ToolRegistry {
+	return &toolRegistry{
+		tools: make(map[string]Tool),
+	}
+}
+
+type toolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]Tool
+}
+
+const DefaultToolTimeout = 30 * time.Second
+
+var (
+	ErrToolInvalid = errors.New("tool must have name and handler")
+	ErrToolExists  = errors.New("tool already registered")
+	ErrToolUnknown = errors.New("unknown tool")
+	ErrToolTimeout = errors.New("tool timed out")
+)